@@ -0,0 +1,252 @@
+package check
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"go.bytecodealliance.org/internal/witcli"
+	"go.bytecodealliance.org/wit"
+)
+
+// Command is the CLI command for check.
+var Command = &cli.Command{
+	Name:  "check",
+	Usage: "validates and lints WIT, exiting with an error if any errors are found",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "strict",
+			Usage: "treat warnings as errors",
+		},
+		&cli.BoolFlag{
+			Name:  "no-docs",
+			Usage: "do not report missing documentation",
+		},
+	},
+	Action: action,
+}
+
+func action(ctx context.Context, cmd *cli.Command) error {
+	path, err := witcli.LoadPath(cmd.Args().Slice()...)
+	if err != nil {
+		return err
+	}
+
+	res, err := witcli.LoadWIT(ctx, path, cmd.Reader, cmd.Bool("force-wit"))
+	if err != nil {
+		return err
+	}
+
+	diags := check(res, !cmd.Bool("no-docs"))
+	printDiagnostics(cmd.Writer, diags)
+
+	var errors int
+	for _, d := range diags {
+		if d.severity == severityError || (d.severity == severityWarning && cmd.Bool("strict")) {
+			errors++
+		}
+	}
+	if errors > 0 {
+		return fmt.Errorf("%d error(s) found", errors)
+	}
+	return nil
+}
+
+type severity int
+
+const (
+	severityError severity = iota
+	severityWarning
+	severityInfo
+)
+
+func (s severity) String() string {
+	switch s {
+	case severityError:
+		return "error"
+	case severityWarning:
+		return "warning"
+	}
+	return "info"
+}
+
+// diagnostic is a single problem found in a [wit.Resolve].
+type diagnostic struct {
+	severity severity
+	subject  string // qualified name of the item, e.g. "wasi:io/streams@0.2.0#input-stream"
+	message  string
+}
+
+func (d diagnostic) String() string {
+	if d.subject == "" {
+		return d.severity.String() + ": " + d.message
+	}
+	return d.severity.String() + ": " + d.subject + ": " + d.message
+}
+
+func printDiagnostics(w io.Writer, diags []diagnostic) {
+	counts := make([]int, severityInfo+1)
+	for _, d := range diags {
+		fmt.Fprintln(w, d.String())
+		counts[d.severity]++
+	}
+	fmt.Fprintf(w, "%d error(s), %d warning(s), %d info\n", counts[severityError], counts[severityWarning], counts[severityInfo])
+}
+
+// check validates res with [wit.Resolve.Validate], and reports interfaces in packages with worlds
+// that are not used by any world or interface, names that are not kebab-case, and,
+// if docs is true, interfaces, worlds, types, and functions without documentation.
+func check(res *wit.Resolve, docs bool) []diagnostic {
+	var diags []diagnostic
+	report := func(s severity, subject, format string, args ...any) {
+		diags = append(diags, diagnostic{s, subject, fmt.Sprintf(format, args...)})
+	}
+
+	if err := res.Validate(); err != nil {
+		report(severityError, "", "%v", err)
+	}
+
+	used := make(map[*wit.Interface]bool)
+	use := func(i *wit.Interface) {
+		used[i] = true
+		for _, dep := range i.Dependencies() {
+			used[dep] = true
+		}
+	}
+	for _, w := range res.Worlds {
+		w.AllInterfaces()(func(_ string, i *wit.Interface) bool {
+			use(i)
+			return true
+		})
+	}
+	for _, i := range res.Interfaces {
+		for _, dep := range i.Dependencies() {
+			used[dep] = true
+		}
+	}
+
+	checkName := func(subject, kind, name string) {
+		if !isKebab(name) {
+			report(severityWarning, subject, "%s name %q is not kebab-case", kind, name)
+		}
+	}
+	checkDocs := func(subject, kind string, d *wit.Docs) {
+		if docs && strings.TrimSpace(d.Contents) == "" {
+			report(severityInfo, subject, "%s has no documentation", kind)
+		}
+	}
+	checkFunction := func(owner string, f *wit.Function) {
+		subject := owner + "#" + f.Name
+		if !f.IsConstructor() {
+			checkName(subject, "function", f.BaseName())
+		}
+		for _, p := range f.Params {
+			checkName(subject, "parameter", p.Name)
+		}
+		for _, p := range f.Results {
+			if p.Name != "" {
+				checkName(subject, "result", p.Name)
+			}
+		}
+		checkDocs(subject, "function", &f.Docs)
+	}
+
+	for _, i := range res.Interfaces {
+		if i.Name == nil {
+			continue
+		}
+		id := i.Package.Name
+		id.Extension = *i.Name
+		name := id.String()
+		checkName(name, "interface", *i.Name)
+		checkDocs(name, "interface", &i.Docs)
+		if !used[i] && i.Package.HasWorlds() {
+			report(severityWarning, name, "interface is not used by any world or interface")
+		}
+		i.Functions.All()(func(_ string, f *wit.Function) bool {
+			checkFunction(name, f)
+			return true
+		})
+	}
+
+	for _, w := range res.Worlds {
+		id := w.Package.Name
+		id.Extension = w.Name
+		name := id.String()
+		checkName(name, "world", w.Name)
+		checkDocs(name, "world", &w.Docs)
+		w.AllItems()(func(_ string, item wit.WorldItem) bool {
+			if f, ok := item.(*wit.Function); ok {
+				checkFunction(name, f)
+			}
+			return true
+		})
+	}
+
+	for _, t := range res.TypeDefs {
+		subject := t.QualifiedName()
+		if subject == "" {
+			continue
+		}
+		checkName(subject, "type", *t.Name)
+		if _, ok := t.Kind.(*wit.TypeDef); !ok {
+			// Type aliases created by use statements are documented where they are defined
+			checkDocs(subject, "type", &t.Docs)
+		}
+		switch kind := t.Kind.(type) {
+		case *wit.Record:
+			for _, f := range kind.Fields {
+				checkName(subject, "field", f.Name)
+			}
+		case *wit.Variant:
+			for _, c := range kind.Cases {
+				checkName(subject, "case", c.Name)
+			}
+		case *wit.Enum:
+			for _, c := range kind.Cases {
+				checkName(subject, "case", c.Name)
+			}
+		case *wit.Flags:
+			for _, f := range kind.Flags {
+				checkName(subject, "flag", f.Name)
+			}
+		}
+	}
+
+	return diags
+}
+
+// isKebab reports whether name is a valid WIT kebab-case name: one or more words separated
+// by single hyphens, where each word begins with a letter, and is either all lowercase or all uppercase.
+func isKebab(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, word := range strings.Split(name, "-") {
+		if word == "" || !isLetter(word[0]) {
+			return false
+		}
+		lower, upper := false, false
+		for i := 0; i < len(word); i++ {
+			c := word[i]
+			switch {
+			case c >= 'a' && c <= 'z':
+				lower = true
+			case c >= 'A' && c <= 'Z':
+				upper = true
+			case c >= '0' && c <= '9':
+			default:
+				return false
+			}
+		}
+		if lower && upper {
+			return false
+		}
+	}
+	return true
+}
+
+func isLetter(c byte) bool { return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' }
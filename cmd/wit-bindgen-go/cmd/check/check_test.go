@@ -0,0 +1,82 @@
+package check
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"go.bytecodealliance.org/wit"
+)
+
+func TestCheck(t *testing.T) {
+	res, err := wit.ParseWIT(strings.NewReader(`package foo:bar;
+
+/// Used by the world.
+interface used {
+	/// A record.
+	record r { a: u32 }
+
+	/// Does f.
+	f: func(x: r);
+	g: func();
+}
+
+/// Not used.
+interface unused {}
+
+/// A world.
+world w {
+	import used;
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// JSON may contain names that ParseWIT would reject
+	res.Interfaces[0].TypeDefs.Get("r").Kind.(*wit.Record).Fields[0].Name = "fieldA"
+
+	var got []string
+	for _, d := range check(res, true) {
+		got = append(got, d.String())
+	}
+	want := []string{
+		"info: foo:bar/used#g: function has no documentation",
+		"warning: foo:bar/unused: interface is not used by any world or interface",
+		`warning: foo:bar/used#r: field name "fieldA" is not kebab-case`,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("check:\n%s\nexpected:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// Validation errors
+	res.TypeDefs = append(res.TypeDefs, &wit.TypeDef{Kind: &wit.Enum{}})
+	diags := check(res, false)
+	if len(diags) == 0 || diags[0].severity != severityError {
+		t.Errorf("check: %v, expected an error for an enum with no cases", diags)
+	}
+}
+
+func TestIsKebab(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"a", true},
+		{"input-stream", true},
+		{"HTTP-request", true},
+		{"ipv4-address", true},
+		{"", false},
+		{"-a", false},
+		{"a-", false},
+		{"a--b", false},
+		{"1a", false},
+		{"a-2b", false},
+		{"camelCase", false},
+		{"snake_case", false},
+	}
+	for _, tt := range tests {
+		if got := isKebab(tt.name); got != tt.want {
+			t.Errorf("isKebab(%q): %t, expected %t", tt.name, got, tt.want)
+		}
+	}
+}
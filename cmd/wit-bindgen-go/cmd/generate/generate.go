@@ -58,6 +58,10 @@ var Command = &cli.Command{
 			Name:  "versioned",
 			Usage: "emit versioned Go package(s) corresponding to WIT package version",
 		},
+		&cli.BoolFlag{
+			Name:  "import-errors",
+			Usage: "recover Go panics in imported function wrappers and return them as an error (does not detect traps)",
+		},
 		&cli.BoolFlag{
 			Name:  "generate-wit",
 			Usage: "generate a WIT file for each generated Go package corresponding to each WIT world or interface",
@@ -72,17 +76,18 @@ var Command = &cli.Command{
 
 // Config is the configuration for the `generate` command.
 type config struct {
-	logger      logging.Logger
-	dryRun      bool
-	out         string
-	outPerm     os.FileMode
-	pkgRoot     string
-	world       string
-	cm          string
-	versioned   bool
-	generateWIT bool
-	forceWIT    bool
-	path        string
+	logger       logging.Logger
+	dryRun       bool
+	out          string
+	outPerm      os.FileMode
+	pkgRoot      string
+	world        string
+	cm           string
+	versioned    bool
+	importErrors bool
+	generateWIT  bool
+	forceWIT     bool
+	path         string
 }
 
 func action(ctx context.Context, cmd *cli.Command) error {
@@ -103,6 +108,7 @@ func action(ctx context.Context, cmd *cli.Command) error {
 		bindgen.World(cfg.world),
 		bindgen.CMPackage(cfg.cm),
 		bindgen.Versioned(cfg.versioned),
+		bindgen.ImportErrors(cfg.importErrors),
 		bindgen.WIT(cfg.generateWIT),
 	)
 	if err != nil {
@@ -147,6 +153,7 @@ func parseFlags(_ context.Context, cmd *cli.Command) (*config, error) {
 		cmd.String("world"),
 		cmd.String("cm"),
 		cmd.Bool("versioned"),
+		cmd.Bool("import-errors"),
 		cmd.Bool("generate-wit"),
 		cmd.Bool("force-wit"),
 		path,
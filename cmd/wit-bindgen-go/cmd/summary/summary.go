@@ -0,0 +1,204 @@
+package summary
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"go.bytecodealliance.org/internal/witcli"
+	"go.bytecodealliance.org/wit"
+)
+
+// Command is the CLI command for summary.
+var Command = &cli.Command{
+	Name:  "summary",
+	Usage: "prints a summary of the imports and exports of a WIT world",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "world",
+			Aliases:  []string{"w"},
+			Value:    "",
+			OnlyOnce: true,
+			Config:   cli.StringConfig{TrimSpace: true},
+			Usage:    "WIT world to summarize (required if more than one world)",
+		},
+		&cli.BoolFlag{
+			Name:  "json",
+			Usage: "print the summary as JSON",
+		},
+	},
+	Action: action,
+}
+
+func action(ctx context.Context, cmd *cli.Command) error {
+	path, err := witcli.LoadPath(cmd.Args().Slice()...)
+	if err != nil {
+		return err
+	}
+
+	res, err := witcli.LoadWIT(ctx, path, cmd.Reader, cmd.Bool("force-wit"))
+	if err != nil {
+		return err
+	}
+
+	w, err := findWorld(res, cmd.String("world"))
+	if err != nil {
+		return err
+	}
+
+	s := summarize(w)
+	if cmd.Bool("json") {
+		enc := json.NewEncoder(cmd.Writer)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(s)
+	}
+	s.print(cmd.Writer)
+	return nil
+}
+
+func findWorld(res *wit.Resolve, pattern string) (*wit.World, error) {
+	if pattern == "" {
+		if len(res.Worlds) != 1 {
+			return nil, fmt.Errorf("found %d worlds, specify a world with --world", len(res.Worlds))
+		}
+		return res.Worlds[0], nil
+	}
+	for _, w := range res.Worlds {
+		if w.Match(pattern) {
+			return w, nil
+		}
+	}
+	return nil, fmt.Errorf("world %s not found", pattern)
+}
+
+// summary is a summary of a WIT world.
+type summary struct {
+	World   string `json:"world"`
+	Imports []item `json:"imports"`
+	Exports []item `json:"exports"`
+}
+
+// item is a summary of a single import or export of a WIT world.
+type item struct {
+	Kind      string     `json:"kind"`
+	Name      string     `json:"name"`
+	Signature string     `json:"signature,omitempty"` // functions
+	Functions []string   `json:"functions,omitempty"` // interfaces
+	Types     []string   `json:"types,omitempty"`     // interfaces
+	Resources []resource `json:"resources,omitempty"` // interfaces or resource types
+}
+
+// resource is a summary of a resource type and its method set.
+type resource struct {
+	Name    string   `json:"name"`
+	Methods []string `json:"methods,omitempty"`
+}
+
+func summarize(w *wit.World) *summary {
+	id := w.Package.Name
+	id.Extension = w.Name
+	s := &summary{World: id.String()}
+	for _, it := range w.SortedImports() {
+		s.Imports = append(s.Imports, summarizeItem(it))
+	}
+	for _, it := range w.SortedExports() {
+		s.Exports = append(s.Exports, summarizeItem(it))
+	}
+	return s
+}
+
+func summarizeItem(it wit.NamedWorldItem) item {
+	switch v := it.Item.(type) {
+	case *wit.InterfaceRef:
+		i := item{Kind: "interface", Name: it.Name}
+		if name := v.Interface.Name; name != nil {
+			id := v.Interface.Package.Name
+			id.Extension = *name
+			i.Name = id.String()
+		}
+		v.Interface.TypeDefs.All()(func(name string, t *wit.TypeDef) bool {
+			if _, ok := t.Kind.(*wit.Resource); ok {
+				i.Resources = append(i.Resources, summarizeResource(t))
+			} else {
+				i.Types = append(i.Types, name)
+			}
+			return true
+		})
+		v.Interface.Functions.All()(func(_ string, f *wit.Function) bool {
+			if f.IsFreestanding() {
+				i.Functions = append(i.Functions, signature(f))
+			}
+			return true
+		})
+		return i
+	case *wit.TypeDef:
+		i := item{Kind: "type", Name: it.Name}
+		if _, ok := v.Kind.(*wit.Resource); ok {
+			i.Resources = []resource{summarizeResource(v)}
+		}
+		return i
+	case *wit.Function:
+		return item{Kind: "function", Name: it.Name, Signature: signature(v)}
+	}
+	return item{Kind: it.Item.WITKind(), Name: it.Name}
+}
+
+func summarizeResource(t *wit.TypeDef) resource {
+	r := resource{Name: t.TypeName()}
+	if f := t.Constructor(); f != nil {
+		r.Methods = append(r.Methods, signature(f))
+	}
+	for _, f := range t.StaticFunctions() {
+		r.Methods = append(r.Methods, signature(f))
+	}
+	for _, f := range t.Methods() {
+		r.Methods = append(r.Methods, signature(f))
+	}
+	return r
+}
+
+// signature returns the WIT signature of f, without a trailing semicolon.
+func signature(f *wit.Function) string {
+	return strings.TrimSuffix(f.WIT(nil, ""), ";")
+}
+
+func (s *summary) print(w io.Writer) {
+	fmt.Fprintf(w, "world %s\n", s.World)
+	printItems(w, "imports", s.Imports)
+	printItems(w, "exports", s.Exports)
+}
+
+func printItems(w io.Writer, label string, items []item) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%s:\n", label)
+	for _, i := range items {
+		if i.Kind == "function" {
+			fmt.Fprintf(w, "  function %s\n", i.Signature)
+			continue
+		}
+		fmt.Fprintf(w, "  %s %s\n", i.Kind, i.Name)
+		for _, t := range i.Types {
+			fmt.Fprintf(w, "    type %s\n", t)
+		}
+		for _, r := range i.Resources {
+			indent := "    "
+			if i.Kind == "interface" {
+				fmt.Fprintf(w, "    resource %s\n", r.Name)
+				indent += "  "
+			}
+			for _, m := range r.Methods {
+				fmt.Fprintf(w, "%s%s\n", indent, m)
+			}
+		}
+		for _, f := range i.Functions {
+			fmt.Fprintf(w, "    %s\n", f)
+		}
+	}
+}
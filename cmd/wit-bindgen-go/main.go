@@ -7,7 +7,9 @@ import (
 
 	"github.com/urfave/cli/v3"
 
+	"go.bytecodealliance.org/cmd/wit-bindgen-go/cmd/check"
 	"go.bytecodealliance.org/cmd/wit-bindgen-go/cmd/generate"
+	"go.bytecodealliance.org/cmd/wit-bindgen-go/cmd/summary"
 	"go.bytecodealliance.org/cmd/wit-bindgen-go/cmd/wit"
 	"go.bytecodealliance.org/internal/witcli"
 )
@@ -25,6 +27,8 @@ var Command = &cli.Command{
 	Usage: "inspect or manipulate WebAssembly Interface Types for Go",
 	Commands: []*cli.Command{
 		generate.Command,
+		check.Command,
+		summary.Command,
 		wit.Command,
 		version,
 	},
@@ -5,6 +5,9 @@ import (
 	"context"
 	"strings"
 	"testing"
+
+	"go.bytecodealliance.org/cmd/wit-bindgen-go/cmd/check"
+	"go.bytecodealliance.org/cmd/wit-bindgen-go/cmd/summary"
 )
 
 // TestSimpleGenVerbosity ensures that a basic generation case honors the verbose flag
@@ -52,3 +55,45 @@ func TestSimpleGenVerbosity(t *testing.T) {
 		t.Errorf("no output was written to stderr when --verbose was used")
 	}
 }
+
+func TestSummary(t *testing.T) {
+	var stdout bytes.Buffer
+	summary.Command.Writer = &stdout
+	defer func() { summary.Command.Writer = nil }()
+
+	err := Command.Run(context.Background(), []string{"wit-bindgen-go", "summary", "../../testdata/wasi/cli-command.wit.json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := stdout.String()
+	for _, want := range []string{
+		"world wasi:cli/command@0.2.0\nimports:\n",
+		"  interface wasi:io/streams@0.2.0\n",
+		"    resource input-stream\n      blocking-read: func(len: u64) -> result<list<u8>, stream-error>\n",
+		"exports:\n  interface wasi:cli/run@0.2.0\n    run: func() -> result\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("summary output did not contain %q", want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	var stdout bytes.Buffer
+	check.Command.Writer = &stdout
+	defer func() { check.Command.Writer = nil }()
+
+	err := Command.Run(context.Background(), []string{"wit-bindgen-go", "check", "--strict", "--no-docs", "../../testdata/wit-parser/resources.wit.json"})
+	if err == nil {
+		t.Error("check --strict: nil error, expected error")
+	}
+	got := stdout.String()
+	for _, want := range []string{
+		"warning: foo:bar/foo: interface is not used by any world or interface\n",
+		"0 error(s), 2 warning(s), 0 info\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("check output did not contain %q:\n%s", want, got)
+		}
+	}
+}
@@ -76,6 +76,60 @@ func HasBorrow(t TypeDefKind) bool {
 	return false
 }
 
+// IsPOD returns whether [Type] t is plain old data: a value that contains no handles and can
+// therefore be freely copied. It returns false if t is or transitively contains an [Own] or
+// [Borrow] resource handle, or a [Future], [Stream], or [ErrorContext], which are also
+// represented as handles. Types that contain pointers, such as [String] or [List],
+// are plain old data if their elements are. Each [TypeDef] is visited at most once.
+func IsPOD(t Type) bool {
+	return isPOD(t, make(map[*TypeDef]bool))
+}
+
+func isPOD(t TypeDefKind, seen map[*TypeDef]bool) bool {
+	switch t := t.(type) {
+	case nil, Primitive, *Enum, *Flags:
+		return true
+	case *TypeDef:
+		if seen[t] {
+			return true
+		}
+		seen[t] = true
+		return isPOD(t.Kind, seen)
+	case *Resource, *Own, *Borrow, *Future, *Stream, *ErrorContext:
+		return false
+	case *Record:
+		for _, f := range t.Fields {
+			if !isPOD(f.Type, seen) {
+				return false
+			}
+		}
+		return true
+	case *Tuple:
+		for _, typ := range t.Types {
+			if !isPOD(typ, seen) {
+				return false
+			}
+		}
+		return true
+	case *Variant:
+		for _, c := range t.Cases {
+			if !isPOD(c.Type, seen) {
+				return false
+			}
+		}
+		return true
+	case *Result:
+		return isPOD(t.OK, seen) && isPOD(t.Err, seen)
+	case *Option:
+		return isPOD(t.Type, seen)
+	case *List:
+		return isPOD(t.Type, seen)
+	case *Pointer:
+		return isPOD(t.Type, seen)
+	}
+	return !HasResource(t)
+}
+
 // LowerFunction returns a [Function] signature for lowering [Type] t.
 func LowerFunction(t Type) *Function {
 	return &Function{
@@ -358,3 +412,74 @@ func compoundParam(singular, plural string, params []Param) Param {
 		Type: PointerTo(t),
 	}
 }
+
+// CanonicalFunc describes a [Function] as imported into or exported from a
+// Core WebAssembly module using the [Canonical ABI].
+//
+// [Canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md
+type CanonicalFunc struct {
+	// Module is the Core WebAssembly module name, e.g. "wasi:random/insecure-seed@0.2.0",
+	// or "$root" for functions imported into or exported from a [World] directly.
+	// Exported functions are named "module#name", or "name" if Module is "$root".
+	Module string
+
+	// Name is the field name, including any [method], [constructor], or [static] prefix,
+	// e.g. "[method]fields.get".
+	Name string
+
+	Direction Direction
+	Function  *Function // the WIT function
+	Core      *Function // the flattened Core WebAssembly function, see [Function.CoreFunction]
+}
+
+// CanonicalFunctions returns the [CanonicalFunc] for each [Function] imported into or
+// exported from each [World] in [Resolve] r. Functions reachable from more than one
+// world with the same module, name, and direction are returned once.
+func (r *Resolve) CanonicalFunctions() []CanonicalFunc {
+	type key struct {
+		module, name string
+		dir          Direction
+	}
+	var funcs []CanonicalFunc
+	seen := make(map[key]bool)
+	add := func(module string, dir Direction, f *Function) {
+		k := key{module, f.Name, dir}
+		if seen[k] {
+			return
+		}
+		seen[k] = true
+		funcs = append(funcs, CanonicalFunc{
+			Module:    module,
+			Name:      f.Name,
+			Direction: dir,
+			Function:  f,
+			Core:      f.CoreFunction(dir),
+		})
+	}
+	each := func(dir Direction) func(string, WorldItem) bool {
+		return func(name string, item WorldItem) bool {
+			switch item := item.(type) {
+			case *InterfaceRef:
+				i := item.Interface
+				module := name
+				if i.Name != nil {
+					id := i.Package.Name
+					id.Extension = *i.Name
+					module = id.String()
+				}
+				i.AllFunctions()(func(f *Function) bool {
+					add(module, dir, f)
+					return true
+				})
+			case *Function:
+				add("$root", dir, item)
+			}
+			return true
+		}
+	}
+	for _, w := range r.Worlds {
+		w.Imports.All()(each(Imported))
+		w.Exports.All()(each(Exported))
+	}
+	return funcs
+}
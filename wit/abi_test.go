@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"math"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -119,6 +120,10 @@ func TestTypeSize(t *testing.T) {
 		{"f64", F64{}, 8, 8},
 		{"char", Char{}, 4, 4},
 		{"string", String{}, 8, 4},
+
+		// Record sizes are padded to a multiple of their alignment.
+		{"record{u64,u32}", &TypeDef{Kind: &Record{Fields: []Field{{Name: "a", Type: U64{}}, {Name: "b", Type: U32{}}}}}, 16, 8},
+		{"record{u32,u8}", &TypeDef{Kind: &Record{Fields: []Field{{Name: "a", Type: U32{}}, {Name: "b", Type: U8{}}}}}, 8, 4},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -211,3 +216,166 @@ func TestHasBorrow(t *testing.T) {
 		})
 	}
 }
+
+func TestIsPOD(t *testing.T) {
+	resource := &TypeDef{Kind: &Resource{}}
+	makeOwn := func() *TypeDef { return &TypeDef{Kind: &Own{Type: resource}} }
+	makeBorrow := func() *TypeDef { return &TypeDef{Kind: &Borrow{Type: resource}} }
+	makeTypeDef := func(kind TypeDefKind) *TypeDef { return &TypeDef{Kind: kind} }
+	cycle := &TypeDef{}
+	cycle.Kind = &List{Type: cycle}
+
+	testCases := []struct {
+		name     string
+		typ      Type
+		expected bool
+	}{
+		{"Primitive", U32{}, true},
+		{"String", String{}, true},
+		{"Own", makeOwn(), false},
+		{"Borrow", makeBorrow(), false},
+		{"Resource", resource, false},
+		{"Record with Own", makeTypeDef(&Record{Fields: []Field{{Type: U8{}}, {Type: makeOwn()}}}), false},
+		{"Record without Own", makeTypeDef(&Record{Fields: []Field{{Type: U8{}}, {Type: String{}}}}), true},
+		{"List of Record with Borrow", makeTypeDef(&List{Type: makeTypeDef(&Record{Fields: []Field{{Type: makeBorrow()}}})}), false},
+		{"List of String", makeTypeDef(&List{Type: String{}}), true},
+		{"Option with Own", makeTypeDef(&Option{Type: makeOwn()}), false},
+		{"Variant with Own", makeTypeDef(&Variant{Cases: []Case{{Name: "a"}, {Name: "b", Type: makeOwn()}}}), false},
+		{"Variant without Own", makeTypeDef(&Variant{Cases: []Case{{Name: "a"}, {Name: "b", Type: U64{}}}}), true},
+		{"Result with Own in Err", makeTypeDef(&Result{OK: String{}, Err: makeOwn()}), false},
+		{"Result without Own", makeTypeDef(&Result{OK: String{}}), true},
+		{"Tuple with Borrow", makeTypeDef(&Tuple{Types: []Type{String{}, makeBorrow()}}), false},
+		{"Enum", makeTypeDef(&Enum{Cases: []EnumCase{{Name: "a"}}}), true},
+		{"Flags", makeTypeDef(&Flags{Flags: []Flag{{Name: "a"}}}), true},
+		{"Future", makeTypeDef(&Future{Type: U8{}}), false},
+		{"Stream", makeTypeDef(&Stream{Element: U8{}}), false},
+		{"ErrorContext", makeTypeDef(&ErrorContext{}), false},
+		{"Alias of Own", makeTypeDef(makeOwn()), false},
+		{"Cycle", cycle, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := IsPOD(tc.typ); result != tc.expected {
+				t.Errorf("IsPOD(%q) = %t; want %t", tc.name, result, tc.expected)
+			}
+		})
+	}
+}
+
+func TestRecordLayout(t *testing.T) {
+	type offset struct {
+		name   string
+		offset uintptr
+		size   uintptr
+	}
+	tests := []struct {
+		name   string
+		fields []Field
+		want   []offset
+		size   uintptr
+	}{
+		{"empty", nil, []offset{}, 0},
+		{"u8", []Field{{Name: "a", Type: U8{}}}, []offset{{"a", 0, 1}}, 1},
+		{
+			"u8,u32,f64",
+			[]Field{{Name: "a", Type: U8{}}, {Name: "b", Type: U32{}}, {Name: "c", Type: F64{}}},
+			[]offset{{"a", 0, 1}, {"b", 4, 4}, {"c", 8, 8}},
+			16,
+		},
+		{
+			"f64,u8,u32",
+			[]Field{{Name: "a", Type: F64{}}, {Name: "b", Type: U8{}}, {Name: "c", Type: U32{}}},
+			[]offset{{"a", 0, 8}, {"b", 8, 1}, {"c", 12, 4}},
+			16,
+		},
+		{
+			"wasi:clocks/wall-clock#datetime",
+			[]Field{{Name: "seconds", Type: U64{}}, {Name: "nanoseconds", Type: U32{}}},
+			[]offset{{"seconds", 0, 8}, {"nanoseconds", 8, 4}},
+			16,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Record{Fields: tt.fields}
+			layout := r.Layout()
+			got := make([]offset, len(layout))
+			for i, f := range layout {
+				got[i] = offset{f.Field.Name, f.Offset, f.Size}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("(*Record).Layout(): %v, expected %v", got, tt.want)
+			}
+			if size := r.Size(); size != tt.size {
+				t.Errorf("(*Record).Size(): %d, expected %d", size, tt.size)
+			}
+		})
+	}
+}
+
+func TestCanonicalFunctions(t *testing.T) {
+	// package wasi:random@0.2.0;
+	// interface insecure-seed { insecure-seed: func() -> tuple<u64, u64>; }
+	//
+	// package test:test;
+	// interface counters { resource counter { get: func() -> u32; } }
+	// world w { import wasi:random/insecure-seed@0.2.0; export counters; }
+	const data = `{
+		"worlds": [{"name": "w", "imports": {"wasi:random/insecure-seed@0.2.0": {"interface": {"id": 0}}}, "exports": {"test:test/counters": {"interface": {"id": 1}}}, "package": 1}],
+		"interfaces": [
+			{"name": "insecure-seed", "types": {}, "functions": {"insecure-seed": {"name": "insecure-seed", "kind": "freestanding", "params": [], "results": [{"type": 0}]}}, "package": 0},
+			{"name": "counters", "types": {"counter": 1}, "functions": {"[method]counter.get": {"name": "[method]counter.get", "kind": {"method": 1}, "params": [{"name": "self", "type": 2}], "results": [{"type": "u32"}]}}, "package": 1}
+		],
+		"types": [
+			{"name": null, "kind": {"tuple": {"types": ["u64", "u64"]}}, "owner": null},
+			{"name": "counter", "kind": "resource", "owner": {"interface": 1}},
+			{"name": null, "kind": {"handle": {"borrow": 1}}, "owner": null}
+		],
+		"packages": [
+			{"name": "wasi:random@0.2.0", "interfaces": {"insecure-seed": 0}, "worlds": {}},
+			{"name": "test:test", "interfaces": {"counters": 1}, "worlds": {"w": 0}}
+		]
+	}`
+	res, err := DecodeJSON(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	funcs := res.CanonicalFunctions()
+	if len(funcs) != 2 {
+		t.Fatalf("CanonicalFunctions(): expected 2 functions, got %d", len(funcs))
+	}
+
+	seed := funcs[0]
+	if got, want := seed.Module, "wasi:random/insecure-seed@0.2.0"; got != want {
+		t.Errorf("Module: got %q, expected %q", got, want)
+	}
+	if got, want := seed.Name, "insecure-seed"; got != want {
+		t.Errorf("Name: got %q, expected %q", got, want)
+	}
+	if got, want := seed.Direction, Imported; got != want {
+		t.Errorf("Direction: got %v, expected %v", got, want)
+	}
+	// The tuple<u64, u64> result does not fit in a single core result, so is returned via pointer.
+	if len(seed.Core.Params) != 1 || len(seed.Core.Results) != 0 {
+		t.Errorf("Core: expected 1 param and 0 results, got %d params and %d results", len(seed.Core.Params), len(seed.Core.Results))
+	} else if _, ok := seed.Core.Params[0].Type.(*TypeDef).Kind.(*Pointer); !ok {
+		t.Errorf("Core: expected pointer param, got %s", seed.Core.Params[0].Type.WIT(nil, ""))
+	}
+
+	get := funcs[1]
+	if got, want := get.Module, "test:test/counters"; got != want {
+		t.Errorf("Module: got %q, expected %q", got, want)
+	}
+	if got, want := get.Name, "[method]counter.get"; got != want {
+		t.Errorf("Name: got %q, expected %q", got, want)
+	}
+	if got, want := get.Direction, Exported; got != want {
+		t.Errorf("Direction: got %v, expected %v", got, want)
+	}
+	want := &Function{Params: []Param{{Name: "self0", Type: U32{}}}, Results: []Param{{Name: "result0", Type: U32{}}}}
+	if !reflect.DeepEqual(get.Core.Params, want.Params) || !reflect.DeepEqual(get.Core.Results, want.Results) {
+		t.Errorf("Core: got %+v -> %+v, expected %+v -> %+v", get.Core.Params, get.Core.Results, want.Params, want.Results)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"go.bytecodealliance.org/internal/go/gen"
+	"go.bytecodealliance.org/wit"
 )
 
 func formatDocComments(s string, indent bool) string {
@@ -24,3 +25,13 @@ func processMarkdown(s string) string {
 	}
 	return strings.Join(lines, "\n")
 }
+
+// deprecatedComment returns a Go "Deprecated:" doc comment paragraph if [wit.Stability] s
+// has a @deprecated gate, or an empty string otherwise.
+func deprecatedComment(s wit.Stability) string {
+	v := wit.DeprecatedVersion(s)
+	if v == nil {
+		return ""
+	}
+	return "//\n// Deprecated: deprecated in version " + v.String() + ".\n"
+}
@@ -0,0 +1,48 @@
+package bindgen
+
+import "go.bytecodealliance.org/wit"
+
+// GoField describes the Go struct field generated for a [wit.Field] of a [wit.Record].
+type GoField struct {
+	// Name is the Go field name, as generated by this package, e.g. "WallClock".
+	Name string
+
+	// WITName is the original WIT field name, e.g. "wall-clock",
+	// suitable for a struct tag such as json:"wall-clock".
+	WITName string
+
+	// Type is a hint for the Go type of the field: the Go type of a primitive type, e.g. "uint32";
+	// the unqualified Go name of a named type, e.g. "DateTime"; or the WIT text of an
+	// anonymous type, e.g. "list<u8>", which this package generates as a cm package type.
+	Type string
+}
+
+// GoStructFields returns the Go struct fields generated for the fields of [wit.Record] r,
+// in declaration order, pairing each Go field name with its WIT name and a Go type hint.
+// Fields are exported if exported is true, which is the case for exported record types.
+func GoStructFields(r *wit.Record, exported bool) []GoField {
+	fields := make([]GoField, len(r.Fields))
+	for i := range r.Fields {
+		f := &r.Fields[i]
+		fields[i] = GoField{
+			Name:    fieldName(f.Name, exported),
+			WITName: f.WITName(),
+			Type:    goTypeHint(f.Type),
+		}
+	}
+	return fields
+}
+
+// goTypeHint returns a hint for the Go type of t. See [GoField].
+func goTypeHint(t wit.Type) string {
+	switch t := t.(type) {
+	case wit.Primitive:
+		return goPrimitive(t)
+	case *wit.TypeDef:
+		if t.Name != nil {
+			return GoName(*t.Name, true)
+		}
+		return t.Kind.WIT(nil, "")
+	}
+	return ""
+}
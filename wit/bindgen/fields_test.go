@@ -0,0 +1,46 @@
+package bindgen
+
+import (
+	"testing"
+
+	"go.bytecodealliance.org/wit"
+)
+
+func TestGoStructFields(t *testing.T) {
+	name := "datetime"
+	datetime := &wit.TypeDef{Name: &name, Kind: &wit.Record{}}
+	r := &wit.Record{Fields: []wit.Field{
+		{Name: "wall-clock", Type: datetime},
+		{Name: "seconds", Type: wit.U64{}},
+		{Name: "type", Type: wit.String{}},
+		{Name: "data", Type: &wit.TypeDef{Kind: &wit.List{Type: wit.U8{}}}},
+	}}
+	tests := []struct {
+		exported bool
+		want     []GoField
+	}{
+		{true, []GoField{
+			{"WallClock", "wall-clock", "DateTime"},
+			{"Seconds", "seconds", "uint64"},
+			{"Type", "type", "string"},
+			{"Data", "data", "list<u8>"},
+		}},
+		{false, []GoField{
+			{"wallClock", "wall-clock", "DateTime"},
+			{"seconds", "seconds", "uint64"},
+			{"type_", "type", "string"},
+			{"data", "data", "list<u8>"},
+		}},
+	}
+	for _, tt := range tests {
+		got := GoStructFields(r, tt.exported)
+		if len(got) != len(tt.want) {
+			t.Fatalf("GoStructFields(exported=%t): %v, expected %v", tt.exported, got, tt.want)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("GoStructFields(exported=%t)[%d]: %v, expected %v", tt.exported, i, got[i], tt.want[i])
+			}
+		}
+	}
+}
@@ -64,6 +64,8 @@ type function struct {
 	receiver param     // The method receiver, if any
 	params   []param   // Function param(s), with unique Go name(s)
 	results  []param   // Function result(s), with unique Go name(s)
+	err      string    // Optional Go name of an additional error result
+	core     bool      // Params and results are Core WebAssembly types, see coreRep
 }
 
 func (f *function) isMethod() bool {
@@ -390,11 +392,13 @@ func (g *generator) defineTypeDef(dir wit.Direction, t *wit.TypeDef, name string
 	if parent != t {
 		// Type alias
 		stringio.Write(&b, "// See [", g.typeRep(decl.file, dir, parent), "] for more information.\n")
+		b.WriteString(deprecatedComment(t.Stability))
 		stringio.Write(&b, "type ", decl.name, " = ", g.typeRep(decl.file, dir, parent), "\n\n")
 	} else {
-		b.WriteString(formatDocComments(t.Docs.Contents, false))
+		b.WriteString(t.Docs.GoComment(""))
 		b.WriteString("//\n")
 		b.WriteString(formatDocComments(t.Kind.WIT(nil, t.TypeName()), true))
+		b.WriteString(deprecatedComment(t.Stability))
 		stringio.Write(&b, "type ", decl.name, " ", g.typeDefRep(decl.file, dir, t, decl.name), "\n\n")
 	}
 
@@ -650,6 +654,8 @@ func (g *generator) typeDefRep(file *gen.File, dir wit.Direction, t *wit.TypeDef
 		return "any /* TODO: *wit.Future */"
 	case *wit.Stream:
 		return "any /* TODO: *wit.Stream */"
+	case *wit.ErrorContext:
+		return "any /* TODO: *wit.ErrorContext */"
 	default:
 		panic(fmt.Sprintf("BUG: unknown wit.TypeDefKind %T", kind)) // should never reach here
 	}
@@ -676,13 +682,58 @@ func (g *generator) typeRep(file *gen.File, dir wit.Direction, t wit.Type) strin
 		// TODO: add wit.Type.BuiltIn() method?
 		return g.typeDefRep(file, dir, t, "")
 	case wit.Primitive:
-		return g.primitiveRep(t)
+		return g.primitiveRep(file, t)
 	default:
 		panic(fmt.Sprintf("BUG: unknown wit.Type %T", t)) // should never reach here
 	}
 }
 
-func (g *generator) primitiveRep(p wit.Primitive) string {
+// coreRep returns the Go type for a flattened Core WebAssembly type t, as used in wasmimport
+// and wasmexport functions and in lift and lower functions. Unlike typeRep, it does not apply
+// the [TypeMapping] to primitive types or pointers to primitive types.
+func (g *generator) coreRep(file *gen.File, dir wit.Direction, t wit.Type) string {
+	switch t := t.(type) {
+	case wit.Primitive:
+		return goPrimitive(t)
+	case *wit.TypeDef:
+		if ptr, ok := t.Kind.(*wit.Pointer); ok {
+			if p, ok := ptr.Type.(wit.Primitive); ok {
+				return "*" + goPrimitive(p)
+			}
+		}
+	}
+	return g.typeRep(file, dir, t)
+}
+
+// addressOf returns the address of Go variable name, converted to pointer type ptr
+// if the pointer refers to a primitive type mapped by the [TypeMapping].
+func (g *generator) addressOf(file *gen.File, dir wit.Direction, ptr wit.Type, name string) string {
+	if g.isMapped(derefPointer(ptr)) {
+		return "(" + g.coreRep(file, dir, ptr) + ")(&" + name + ")"
+	}
+	return "&" + name
+}
+
+// isMapped returns true if t is a primitive type mapped to another Go type by the [TypeMapping].
+func (g *generator) isMapped(t wit.Type) bool {
+	p, ok := t.(wit.Primitive)
+	return ok && g.opts.typeMapping.lookup(p) != ""
+}
+
+func (g *generator) primitiveRep(file *gen.File, p wit.Primitive) string {
+	name := g.opts.typeMapping.lookup(p)
+	if name == "" {
+		return goPrimitive(p)
+	}
+	i := strings.LastIndex(name, ".")
+	if i < 0 || i < strings.LastIndex(name, "/") {
+		return name
+	}
+	return file.Import(name[:i]) + "." + name[i+1:]
+}
+
+// goPrimitive returns the Go type for WIT primitive type p.
+func goPrimitive(p wit.Primitive) string {
 	switch p := p.(type) {
 	case wit.Bool:
 		return "bool"
@@ -724,7 +775,7 @@ func (g *generator) recordRep(file *gen.File, dir wit.Direction, r *wit.Record,
 		if i == 0 || i > 0 && f.Docs.Contents != "" {
 			b.WriteRune('\n')
 		}
-		b.WriteString(formatDocComments(f.Docs.Contents, false))
+		b.WriteString(f.Docs.GoComment(""))
 		stringio.Write(&b, fieldName(f.Name, exported), " ", g.typeRep(file, dir, f.Type), "\n")
 	}
 	b.WriteRune('}')
@@ -793,7 +844,7 @@ func (g *generator) flagsRep(file *gen.File, dir wit.Direction, flags *wit.Flags
 		if i > 0 && flag.Docs.Contents != "" {
 			b.WriteRune('\n')
 		}
-		b.WriteString(formatDocComments(flag.Docs.Contents, false))
+		b.WriteString(flag.Docs.GoComment(""))
 		flagName := file.DeclareName(goName + GoName(flag.Name, true))
 		b.WriteString(flagName)
 		if i == 0 {
@@ -815,7 +866,7 @@ func (g *generator) enumRep(file *gen.File, dir wit.Direction, e *wit.Enum, goNa
 		if i > 0 && c.Docs.Contents != "" {
 			b.WriteRune('\n')
 		}
-		b.WriteString(formatDocComments(c.Docs.Contents, false))
+		b.WriteString(c.Docs.GoComment(""))
 		b.WriteString(file.DeclareName(goName + GoName(c.Name, true)))
 		if i == 0 {
 			b.WriteRune(' ')
@@ -879,7 +930,7 @@ func (g *generator) variantRep(file *gen.File, dir wit.Direction, t *wit.TypeDef
 		// Emit constructor
 		stringio.Write(&b, "// ", constructorName, " returns a [", goName, "] of case \"", c.Name, "\".\n")
 		b.WriteString("//\n")
-		b.WriteString(formatDocComments(c.Docs.Contents, false))
+		b.WriteString(c.Docs.GoComment(""))
 		stringio.Write(&b, "func ", constructorName, "(")
 		dataName := "data"
 		if c.Type != nil {
@@ -1078,6 +1129,8 @@ func (g *generator) lowerTypeDef(file *gen.File, dir wit.Direction, t *wit.TypeD
 		return "/* TODO: lower *wit.Future */"
 	case *wit.Stream:
 		return "/* TODO: lower *wit.Stream */"
+	case *wit.ErrorContext:
+		return "/* TODO: lower *wit.ErrorContext */"
 	default:
 		panic(fmt.Sprintf("BUG: unknown wit.TypeDef %T", kind)) // should never reach here
 	}
@@ -1090,6 +1143,7 @@ func (g *generator) typeDefLowerFunction(file *gen.File, dir wit.Direction, t *w
 		abiFile := g.abiFile(file.Package)
 		name := abiFile.DeclareName("lower_" + g.typeDefGoName(dir, t))
 		f = g.goFunction(abiFile, dir, wit.Imported, wit.LowerFunction(t), name)
+		f.core = true
 		g.lowerFunctions[use] = f
 		stringio.Write(abiFile, "func ", name, g.functionSignature(abiFile, f), " {\n", body, "}\n\n")
 	}
@@ -1243,11 +1297,21 @@ func (g *generator) liftTypeInput(file *gen.File, dir wit.Direction, t wit.Type,
 		if i > 0 {
 			b.WriteString(", ")
 		}
-		b.WriteString(g.cast(file, dir, p.typ, flat[i], p.name))
+		b.WriteString(g.liftCast(file, dir, t, i, p.typ, flat[i], p.name))
 	}
 	return b.String()
 }
 
+// liftCast casts input, the flat value at index i of type t, from type from to type to.
+// The data pointer of a list is cast to a pointer to the Go element type of the list,
+// which may differ from its Core WebAssembly type if mapped by the [TypeMapping].
+func (g *generator) liftCast(file *gen.File, dir wit.Direction, t wit.Type, i int, from, to wit.Type, input string) string {
+	if l := wit.KindOf[*wit.List](t); l != nil && i == 0 && g.isMapped(derefPointer(from)) {
+		return "(" + g.typeRep(file, dir, to) + ")(" + input + ")"
+	}
+	return g.cast(file, dir, from, to, input)
+}
+
 func (g *generator) liftType(file *gen.File, dir wit.Direction, t wit.Type, input string) string {
 	switch t := t.(type) {
 	case nil:
@@ -1295,6 +1359,8 @@ func (g *generator) liftTypeDef(file *gen.File, dir wit.Direction, t *wit.TypeDe
 		return "// TODO: lift *wit.Future */"
 	case *wit.Stream:
 		return "// TODO: lift *wit.Stream */"
+	case *wit.ErrorContext:
+		return "// TODO: lift *wit.ErrorContext */"
 	default:
 		panic(fmt.Sprintf("BUG: unknown wit.TypeDef %T", kind)) // should never reach here
 	}
@@ -1307,6 +1373,7 @@ func (g *generator) typeDefLiftFunction(file *gen.File, dir wit.Direction, t *wi
 		abiFile := g.abiFile(file.Package)
 		name := abiFile.DeclareName("lift_" + g.typeDefGoName(dir, t))
 		f = g.goFunction(abiFile, dir, wit.Imported, wit.LiftFunction(t), name)
+		f.core = true
 		g.liftFunctions[use] = f
 		stringio.Write(abiFile, "func ", name, g.functionSignature(abiFile, f), " {\n", body, "}\n\n")
 	}
@@ -1426,7 +1493,7 @@ func (g *generator) liftVariantCase(file *gen.File, dir wit.Direction, t wit.Typ
 		if i > 0 {
 			b.WriteString(", ")
 		}
-		stringio.Write(&b, g.cast(file, dir, from[i], f, "f"+strconv.Itoa(i+1)))
+		stringio.Write(&b, g.liftCast(file, dir, t, i, from[i], f, "f"+strconv.Itoa(i+1)))
 	}
 	return g.liftType(file, dir, t, b.String())
 }
@@ -1444,13 +1511,22 @@ func (g *generator) liftPrimitive(file *gen.File, dir wit.Direction, t wit.Type,
 	case wit.String:
 		return g.cmCall(file, "LiftString["+g.typeRep(file, dir, t)+"]", input)
 	default:
-		return g.cast(file, dir, flat[0], t, input)
+		if !g.isMapped(t) {
+			return g.cast(file, dir, flat[0], t, input)
+		}
+		if castable(flat[0], t) {
+			return "(" + g.typeRep(file, dir, t) + ")(" + input + ")"
+		}
+		return "(" + g.typeRep(file, dir, t) + ")(" + g.cast(file, dir, flat[0], t, input) + ")"
 	}
 }
 
 func (g *generator) cast(file *gen.File, dir wit.Direction, from, to wit.Type, input string) string {
 	if castable(from, to) {
-		return "(" + g.typeRep(file, dir, to) + ")(" + input + ")"
+		return "(" + g.coreRep(file, dir, to) + ")(" + input + ")"
+	}
+	if g.isMapped(from) {
+		input = g.coreRep(file, dir, from) + "(" + input + ")"
 	}
 	t := derefPointer(to)
 	if t != nil {
@@ -1670,6 +1746,10 @@ func (g *generator) declareFunction(owner wit.TypeOwner, dir wit.Direction, f *w
 		wasmFunc:   g.goFunction(wasmFile, tdir, dir, wasm, wasmName),
 		linkerName: linkerName,
 	}
+	fdecl.wasmFunc.core = true
+	if dir == wit.Imported && g.opts.importErrors {
+		fdecl.goFunc.err = fdecl.goFunc.scope.DeclareName("err")
+	}
 	g.functions[dir][f] = fdecl
 	return fdecl, nil
 }
@@ -1756,7 +1836,10 @@ func (g *generator) defineImportedFunction(decl *funcDecl) error {
 	b.WriteString(g.functionDocs(dir, decl.f, decl.goFunc.name))
 
 	// Emit Go function
-	b.WriteString("//go:nosplit\n")
+	if decl.goFunc.err == "" {
+		// Functions that recover from panics with a deferred call cannot be nosplit.
+		b.WriteString("//go:nosplit\n")
+	}
 	b.WriteString("func ")
 	if decl.goFunc.isMethod() {
 		stringio.Write(&b, "(", decl.goFunc.receiver.name, " ", g.typeRep(file, decl.goFunc.receiver.dir, decl.goFunc.receiver.typ), ") ", decl.goFunc.name)
@@ -1768,6 +1851,16 @@ func (g *generator) defineImportedFunction(decl *funcDecl) error {
 	// Emit function body
 	b.WriteString(" {\n")
 
+	// Recover from panics, returning an error
+	if decl.goFunc.err != "" {
+		x := decl.goFunc.scope.DeclareName("x")
+		b.WriteString("defer func() {\n")
+		stringio.Write(&b, "if ", x, " := recover(); ", x, " != nil {\n")
+		stringio.Write(&b, decl.goFunc.err, " = ", file.Import("fmt"), ".Errorf(\"", decl.f.Name, ": %v\", ", x, ")\n")
+		b.WriteString("}\n")
+		b.WriteString("}()\n")
+	}
+
 	// Lower into wasmimport variables
 	if pointerParam.typ != nil {
 		stringio.Write(&b, callParams[0].name, " := &", decl.goFunc.params[0].name, "\n")
@@ -1819,8 +1912,7 @@ func (g *generator) defineImportedFunction(decl *funcDecl) error {
 		t := derefPointer(p.typ)
 		// TODO: this logic is ugly
 		if t != nil && (t == compoundParams.typ || t == compoundResults.typ || p.typ == pointerResult.typ) {
-			b.WriteRune('&')
-			b.WriteString(p.name)
+			b.WriteString(g.addressOf(file, p.dir, p.typ, p.name))
 		} else {
 			b.WriteString(g.cast(file, p.dir, p.typ, p.typ, p.name))
 		}
@@ -1835,6 +1927,9 @@ func (g *generator) defineImportedFunction(decl *funcDecl) error {
 			}
 			stringio.Write(&b, compoundResults.name, ".", fieldName(f.Name, false))
 		}
+		if decl.goFunc.err != "" {
+			b.WriteString(", nil")
+		}
 		b.WriteString("\n")
 	} else if len(callResults) > 0 {
 		i := 0
@@ -2018,7 +2113,7 @@ func (g *generator) defineExportedFunction(decl *funcDecl) error {
 			if i < len(decl.wasmFunc.results) {
 				wr := decl.wasmFunc.results[i]
 				if r.typ == derefPointer(wr.typ) {
-					stringio.Write(wasmFile, wr.name, " = &", r.name, "\n")
+					stringio.Write(wasmFile, wr.name, " = ", g.addressOf(wasmFile, wr.dir, wr.typ, r.name), "\n")
 					i++
 					continue
 				}
@@ -2074,6 +2169,10 @@ func (g *generator) defineExportedFunction(decl *funcDecl) error {
 
 func (g *generator) functionSignature(file *gen.File, f function) string {
 	var b strings.Builder
+	rep := g.typeRep
+	if f.core {
+		rep = g.coreRep
+	}
 
 	b.WriteRune('(')
 
@@ -2086,20 +2185,26 @@ func (g *generator) functionSignature(file *gen.File, f function) string {
 		if i > 0 {
 			b.WriteString(", ")
 		}
-		stringio.Write(&b, p.name, " ", g.typeRep(file, p.dir, p.typ))
+		stringio.Write(&b, p.name, " ", rep(file, p.dir, p.typ))
 	}
 	b.WriteString(") ")
 
 	// Emit results
-	if len(f.results) == 1 && f.results[0].name == "" {
-		b.WriteString(g.typeRep(file, f.results[0].dir, f.results[0].typ))
-	} else if len(f.results) > 0 {
+	if len(f.results) == 1 && f.results[0].name == "" && f.err == "" {
+		b.WriteString(rep(file, f.results[0].dir, f.results[0].typ))
+	} else if len(f.results) > 0 || f.err != "" {
 		b.WriteRune('(')
 		for i, r := range f.results {
 			if i > 0 {
 				b.WriteString(", ")
 			}
-			stringio.Write(&b, r.name, " ", g.typeRep(file, r.dir, r.typ))
+			stringio.Write(&b, r.name, " ", rep(file, r.dir, r.typ))
+		}
+		if f.err != "" {
+			if len(f.results) > 0 {
+				b.WriteString(", ")
+			}
+			stringio.Write(&b, f.err, " error")
 		}
 		b.WriteRune(')')
 	}
@@ -2178,13 +2283,14 @@ func (g *generator) functionDocs(dir wit.Direction, f *wit.Function, goName stri
 	}
 	if f.Docs.Contents != "" {
 		b.WriteString("//\n")
-		b.WriteString(formatDocComments(f.Docs.Contents, false))
+		b.WriteString(f.Docs.GoComment(""))
 	}
 	b.WriteString("//\n")
 	if !f.IsAdmin() {
 		w := strings.TrimSuffix(f.WIT(nil, f.BaseName()), ";")
 		b.WriteString(formatDocComments(w, true))
 	}
+	b.WriteString(deprecatedComment(f.Stability))
 	return b.String()
 }
 
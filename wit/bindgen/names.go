@@ -17,7 +17,17 @@ func GoPackageName(name string) string {
 	}, strings.ToLower(name))
 }
 
-// GoName returns an idiomatic (exported CamelCase) Go name for a WIT name.
+// GoName returns an idiomatic CamelCase Go name for a kebab-case WIT name,
+// exported if export is true, e.g. "get-time" maps to "getTime" or "GetTime".
+//
+// Each hyphen-separated segment of name is mapped according to these rules, in order:
+//   - The first segment of a non-exported name is lowercased, or mapped via [Segments].
+//   - An all-UPPERCASE segment is preserved, e.g. "time-EOD" maps to "timeEOD".
+//   - A segment in [ExportedSegments] is mapped to its opinionated form, e.g. "ipv4" to "IPv4".
+//   - A common initialism, such as "id" or "url", is uppercased.
+//   - Any other segment is title-cased.
+//
+// The returned name may be a Go keyword or predeclared identifier. See [GoUnexportedName].
 func GoName(name string, export bool) string {
 	var b strings.Builder
 	for i, segment := range segments(name) {
@@ -51,6 +61,29 @@ func GoName(name string, export bool) string {
 	return b.String()
 }
 
+// GoExportName returns an exported Go name for a WIT name, e.g. "wall-clock" maps to "WallClock".
+// It is equivalent to GoName(name, true).
+func GoExportName(name string) string {
+	return GoName(name, true)
+}
+
+// GoUnexportedName returns a non-exported Go name for a WIT name, e.g. "wall-clock" maps to "wallClock".
+// The returned name is escaped with [SafeGoName] if necessary.
+func GoUnexportedName(name string) string {
+	return SafeGoName(name)
+}
+
+// SafeGoName returns a non-exported Go name for a WIT name that is safe to use as a Go identifier,
+// such as a function parameter. If the name maps to a [Go keyword] or [predeclared identifier],
+// such as "type", "range", or "string", an underscore is appended to it, e.g. "type_".
+// Exported names never collide, as Go keywords and predeclared identifiers are lowercase.
+//
+// [Go keyword]: https://go.dev/ref/spec#Keywords
+// [predeclared identifier]: https://go.dev/ref/spec#Predeclared_identifiers
+func SafeGoName(name string) string {
+	return gen.UniqueName(GoName(name, false), gen.IsReserved)
+}
+
 // SnakeName returns a snake_case equivalent of a WIT name.
 // It may conflict with a Go keyword or predeclared identifier.
 func SnakeName(name string) string {
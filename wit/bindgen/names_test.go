@@ -1,6 +1,10 @@
 package bindgen
 
-import "testing"
+import (
+	"go/token"
+	"go/types"
+	"testing"
+)
 
 func TestGoName(t *testing.T) {
 	tests := []struct {
@@ -35,3 +39,57 @@ func TestGoName(t *testing.T) {
 		})
 	}
 }
+
+func TestGoExportName(t *testing.T) {
+	tests := []struct {
+		name       string
+		exported   string
+		unexported string
+	}{
+		{"wall-clock", "WallClock", "wallClock"},
+		{"get-time", "GetTime", "getTime"},
+		{"user-id", "UserID", "userID"},
+		{"base-url", "BaseURL", "baseURL"},
+		{"id", "ID", "id"},
+		{"type", "Type", "type_"},
+		{"range", "Range", "range_"},
+		{"string", "String", "string_"},
+		{"error-code", "ErrorCode", "errorCode"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GoExportName(tt.name); got != tt.exported {
+				t.Errorf("GoExportName(%q): %q, expected %q", tt.name, got, tt.exported)
+			}
+			if got := GoUnexportedName(tt.name); got != tt.unexported {
+				t.Errorf("GoUnexportedName(%q): %q, expected %q", tt.name, got, tt.unexported)
+			}
+		})
+	}
+}
+
+func TestSafeGoName(t *testing.T) {
+	var names []string
+	for tok := token.BREAK; tok <= token.VAR; tok++ {
+		if tok.IsKeyword() {
+			names = append(names, tok.String())
+		}
+	}
+	if len(names) != 25 {
+		t.Errorf("found %d Go keywords, expected 25", len(names))
+	}
+	names = append(names, types.Universe.Names()...)
+	for _, name := range names {
+		if got, want := SafeGoName(name), name+"_"; got != want {
+			t.Errorf("SafeGoName(%q): %q, expected %q", name, got, want)
+		}
+		if got := GoExportName(name); token.IsKeyword(got) || types.Universe.Lookup(got) != nil {
+			t.Errorf("GoExportName(%q): %q collides with a Go keyword or predeclared identifier", name, got)
+		}
+	}
+	for _, name := range []string{"wall-clock", "types", "ranges", "error-code"} {
+		if got := SafeGoName(name); got != GoName(name, false) {
+			t.Errorf("SafeGoName(%q): %q, expected %q", name, got, GoName(name, false))
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package bindgen
 
 import (
+	"go.bytecodealliance.org/wit"
 	"go.bytecodealliance.org/wit/logging"
 )
 
@@ -37,6 +38,13 @@ type options struct {
 
 	// generateWIT determines if WIT files will be generated for each world and interface.
 	generateWIT bool
+
+	// importErrors determines if imported functions return an error rather than panic
+	// when a failure is detected in the Go wrapper for the imported function.
+	importErrors bool
+
+	// typeMapping overrides the Go types generated for WIT primitive types.
+	typeMapping TypeMapping
 }
 
 func (opts *options) apply(o ...Option) error {
@@ -109,3 +117,88 @@ func WIT(generateWIT bool) Option {
 		return nil
 	})
 }
+
+// ImportErrors returns an [Option] that specifies whether generated wrappers for
+// imported functions recover Go panics and return them as an additional error result.
+//
+// Only Go panics raised in the wrapper itself, while lowering arguments or lifting
+// results, are recovered. The Canonical ABI has no way to report a failure of the
+// imported function: a trap aborts the component instance and is never observed by Go code.
+// Wrappers generated with this option are not marked //go:nosplit.
+// By default, wrappers do not recover from panics.
+func ImportErrors(importErrors bool) Option {
+	return optionFunc(func(opts *options) error {
+		opts.importErrors = importErrors
+		return nil
+	})
+}
+
+// TypeMapping overrides the Go type generated for each WIT primitive type.
+// An empty field uses the default Go type, listed below for each field.
+//
+// Each field is either a predeclared Go type, e.g. "uint32", or a qualified type name
+// in the form "<import path>.<Name>", e.g. "example.com/bigint.U64". The package name of
+// the imported package must match the last element of its import path. The Go type must
+// have the default Go type as its underlying type.
+//
+// The mapping applies to the Go types of function params and results, record fields,
+// and the type arguments of generic types such as cm.List and cm.Option. The wasmimport
+// and wasmexport functions keep their Core WebAssembly types, and generated code
+// converts between the two when lifting and lowering values.
+type TypeMapping struct {
+	Bool   string // default: bool
+	S8     string // default: int8
+	U8     string // default: uint8
+	S16    string // default: int16
+	U16    string // default: uint16
+	S32    string // default: int32
+	U32    string // default: uint32
+	S64    string // default: int64
+	U64    string // default: uint64
+	F32    string // default: float32
+	F64    string // default: float64
+	Char   string // default: rune
+	String string // default: string
+}
+
+// lookup returns the Go type mapped to WIT primitive type p, or an empty string if not mapped.
+func (m *TypeMapping) lookup(p wit.Primitive) string {
+	switch p.(type) {
+	case wit.Bool:
+		return m.Bool
+	case wit.S8:
+		return m.S8
+	case wit.U8:
+		return m.U8
+	case wit.S16:
+		return m.S16
+	case wit.U16:
+		return m.U16
+	case wit.S32:
+		return m.S32
+	case wit.U32:
+		return m.U32
+	case wit.S64:
+		return m.S64
+	case wit.U64:
+		return m.U64
+	case wit.F32:
+		return m.F32
+	case wit.F64:
+		return m.F64
+	case wit.Char:
+		return m.Char
+	case wit.String:
+		return m.String
+	}
+	return ""
+}
+
+// MapTypes returns an [Option] that specifies a [TypeMapping] to override the
+// Go types generated for WIT primitive types, e.g. to generate char as uint32.
+func MapTypes(m TypeMapping) Option {
+	return optionFunc(func(opts *options) error {
+		opts.typeMapping = m
+		return nil
+	})
+}
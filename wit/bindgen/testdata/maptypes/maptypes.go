@@ -0,0 +1,14 @@
+// Package maptypes declares named Go types used to test [bindgen.MapTypes].
+package maptypes
+
+// U is a named uint32.
+type U uint32
+
+// Str is a named string.
+type Str string
+
+// F is a named float32.
+type F float32
+
+// B is a named bool.
+type B bool
@@ -23,7 +23,10 @@ import (
 	"go.bytecodealliance.org/wit"
 )
 
-var writeGoFiles = flag.Bool("write", false, "write generated Go files")
+var (
+	writeGoFiles = flag.Bool("write", false, "write generated Go files")
+	update       = flag.Bool("update", false, "update golden files")
+)
 
 const (
 	testdataPath  = "../../testdata"
@@ -79,7 +82,7 @@ var canGo = sync.OnceValue[bool](func() bool {
 })
 
 // validateGeneratedGo loads the Go package(s) generated
-func validateGeneratedGo(t *testing.T, res *wit.Resolve, origin string) {
+func validateGeneratedGo(t *testing.T, res *wit.Resolve, origin string, opts ...Option) {
 	if !canGo() {
 		t.Log("skipping test: can't run go (TinyGo without fork?)")
 		return
@@ -104,11 +107,11 @@ func validateGeneratedGo(t *testing.T, res *wit.Resolve, origin string) {
 		return
 	}
 
-	pkgs, err := Go(res,
+	pkgs, err := Go(res, append([]Option{
 		GeneratedBy("test"),
 		PackageRoot(pkgPath),
 		Versioned(true),
-	)
+	}, opts...)...)
 	if err != nil {
 		t.Error(err)
 		return
@@ -226,3 +229,218 @@ func TestGenerateTestdata(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+// TestImportErrors verifies that imported functions return an error when generated with [ImportErrors].
+func TestImportErrors(t *testing.T) {
+	if testing.Short() {
+		// t.Skip is not available in TinyGo, requires runtime.Goexit()
+		return
+	}
+	const path = testdataPath + "/codegen/import-func.wit.json"
+	res, err := wit.LoadJSON(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pkgs, err := Go(res, GeneratedBy("test"), PackageRoot("example.com/import-errors"), ImportErrors(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var src []byte
+	for _, pkg := range pkgs {
+		if f := pkg.Files["foo.wit.go"]; f != nil {
+			src, err = f.Bytes()
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if src == nil {
+		t.Fatal("foo.wit.go not generated")
+	}
+
+	golden := "testdata/import-errors.wit.go.golden"
+	if *update {
+		err := os.WriteFile(golden, src, 0o644)
+		if err != nil {
+			t.Error(err)
+		}
+	} else {
+		want, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(want) != string(src) {
+			t.Errorf("generated Go for %s did not match golden file %s:\n%s", path, golden, src)
+		}
+	}
+
+	validateGeneratedGo(t, res, "import-errors", ImportErrors(true))
+}
+
+// TestMapTypes verifies that primitive types are generated with the Go types specified with [MapTypes].
+func TestMapTypes(t *testing.T) {
+	if testing.Short() {
+		// t.Skip is not available in TinyGo, requires runtime.Goexit()
+		return
+	}
+	const path = testdataPath + "/codegen/char.wit.json"
+	res, err := wit.LoadJSON(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		m     TypeMapping
+		wants []string
+	}{
+		{"default", TypeMapping{}, []string{"func TakeChar(x rune)", "func ReturnChar() (result rune)"}},
+		{"uint32", TypeMapping{Char: "uint32"}, []string{"func TakeChar(x uint32)", "func ReturnChar() (result uint32)"}},
+		{"qualified", TypeMapping{Char: "example.com/text.Char"}, []string{`"example.com/text"`, "func TakeChar(x text.Char)"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkgs, err := Go(res, GeneratedBy("test"), PackageRoot("example.com/map-types"), MapTypes(tt.m))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var src []byte
+			for _, pkg := range pkgs {
+				if f := pkg.Files["chars.wit.go"]; f != nil {
+					src, err = f.Bytes()
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			if src == nil {
+				t.Fatal("chars.wit.go not generated")
+			}
+			for _, want := range tt.wants {
+				if !strings.Contains(string(src), want) {
+					t.Errorf("generated Go does not contain %q:\n%s", want, src)
+				}
+			}
+		})
+	}
+
+	validateGeneratedGo(t, res, "map-types", MapTypes(TypeMapping{Char: "uint32"}))
+}
+
+// TestMapTypesNamed verifies that primitive types mapped to named Go types are used in
+// Go-facing signatures and fields, while wasmimport and wasmexport functions keep
+// their Core WebAssembly types, converting between them when lifting and lowering.
+func TestMapTypesNamed(t *testing.T) {
+	if testing.Short() {
+		// t.Skip is not available in TinyGo, requires runtime.Goexit()
+		return
+	}
+	res, err := wit.ParseWIT(strings.NewReader(`package foo:bar;
+
+interface i {
+	record r { a: u32, s: string, f: f32, b: bool }
+	variant v { a(u32), f(f32), s(string), b(bool) }
+	f: func(a: u32, s: string, x: f32, b: bool) -> u32;
+	g: func() -> string;
+	h: func(r: r, l: list<u32>, o: option<u32>) -> result<string, u32>;
+	k: func(v: v) -> tuple<u32, string>;
+	many: func(a: u32, b: u32, c: u32, d: u32, e: u32, f: u32, g: u32, h: u32, i: u32, j: u32, k: u32, l: u32, m: u32, n: u32, o: u32, p: string) -> (x: u32, y: string);
+}
+
+world w {
+	import i;
+	export i;
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const pkg = "go.bytecodealliance.org/wit/bindgen/testdata/maptypes"
+	m := TypeMapping{
+		U32:    pkg + ".U",
+		String: pkg + ".Str",
+		F32:    pkg + ".F",
+		Bool:   pkg + ".B",
+	}
+
+	pkgs, err := Go(res, GeneratedBy("test"), PackageRoot("example.com/map-types"), MapTypes(m))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var src []byte
+	for _, pkg := range pkgs {
+		if f := pkg.Files["i.wit.go"]; f != nil && strings.Contains(pkg.Path, "/foo/bar/i") {
+			src, err = f.Bytes()
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	for _, want := range []string{
+		"func F(a maptypes.U, s maptypes.Str, x maptypes.F, b maptypes.B) (result maptypes.U)",
+		"func G() (result maptypes.Str)",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generated Go does not contain %q:\n%s", want, src)
+		}
+	}
+
+	validateGeneratedGo(t, res, "map-types-named", MapTypes(m))
+}
+
+// TestDeprecated verifies that types and functions with a @deprecated gate are generated
+// with a Go "Deprecated:" doc comment.
+func TestDeprecated(t *testing.T) {
+	res, err := wit.ParseWIT(strings.NewReader(`package foo:bar@1.0.0;
+
+interface i {
+	@since(version = 1.0.0)
+	@deprecated(version = 1.0.1)
+	type old = u32;
+
+	@since(version = 1.0.0)
+	@deprecated(version = 1.0.2)
+	old-func: func(x: old);
+
+	@since(version = 1.0.0)
+	new-func: func();
+}
+
+world w {
+	@since(version = 1.0.0)
+	import i;
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkgs, err := Go(res, GeneratedBy("test"), PackageRoot("example.com/deprecated"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var src string
+	for _, pkg := range pkgs {
+		if f := pkg.Files["i.wit.go"]; f != nil {
+			b, err := f.Bytes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			src = string(b)
+		}
+	}
+	if src == "" {
+		t.Fatal("i.wit.go not generated")
+	}
+	for _, want := range []string{
+		"// Deprecated: deprecated in version 1.0.1.\ntype Old uint32",
+		"// Deprecated: deprecated in version 1.0.2.\n//\n//go:nosplit\nfunc OldFunc(",
+	} {
+		if !strings.Contains(src, want) {
+			t.Errorf("generated Go does not contain %q:\n%s", want, src)
+		}
+	}
+	if n := strings.Count(src, "Deprecated:"); n != 2 {
+		t.Errorf("generated Go contains %d Deprecated comments, expected 2:\n%s", n, src)
+	}
+}
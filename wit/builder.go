@@ -0,0 +1,231 @@
+package wit
+
+import "fmt"
+
+// ResolveBuilder builds a [Resolve] programmatically, for example to construct
+// test fixtures for code generators without WIT or JSON input.
+// Each Add method creates an item and links it to its [Package] or owner,
+// returning the new item so it can be referred to by later items.
+//
+// The first error encountered, such as an invalid or duplicate name, is recorded
+// and returned by [ResolveBuilder.Build]. Add methods called after an error return nil.
+type ResolveBuilder struct {
+	res *Resolve
+	err error
+}
+
+// NewResolveBuilder returns a new, empty [ResolveBuilder].
+func NewResolveBuilder() *ResolveBuilder {
+	return &ResolveBuilder{res: &Resolve{}}
+}
+
+func (b *ResolveBuilder) errorf(format string, args ...any) {
+	if b.err == nil {
+		b.err = fmt.Errorf(format, args...)
+	}
+}
+
+// AddPackage adds a [Package] named name, e.g. "wasi:clocks@0.2.0".
+func (b *ResolveBuilder) AddPackage(name string) *Package {
+	if b.err != nil {
+		return nil
+	}
+	id, err := ParseIdent(name)
+	if err == nil {
+		err = id.Validate()
+	}
+	if err != nil {
+		b.errorf("package %s: %w", name, err)
+		return nil
+	}
+	for _, p := range b.res.Packages {
+		if p.Name.String() == id.String() {
+			b.errorf("duplicate package %s", name)
+			return nil
+		}
+	}
+	p := &Package{Name: id}
+	b.res.Packages = append(b.res.Packages, p)
+	return p
+}
+
+// AddInterface adds an [Interface] named name to [Package] p.
+func (b *ResolveBuilder) AddInterface(p *Package, name string) *Interface {
+	if b.err != nil {
+		return nil
+	}
+	if _, ok := p.Interfaces.GetOK(name); ok {
+		b.errorf("duplicate interface %s in package %s", name, p.Name.String())
+		return nil
+	}
+	i := &Interface{Name: &name, Package: p}
+	p.Interfaces.Set(name, i)
+	b.res.Interfaces = append(b.res.Interfaces, i)
+	return i
+}
+
+// AddWorld adds a [World] named name to [Package] p.
+func (b *ResolveBuilder) AddWorld(p *Package, name string) *World {
+	if b.err != nil {
+		return nil
+	}
+	if _, ok := p.Worlds.GetOK(name); ok {
+		b.errorf("duplicate world %s in package %s", name, p.Name.String())
+		return nil
+	}
+	w := &World{Name: name, Package: p}
+	p.Worlds.Set(name, w)
+	b.res.Worlds = append(b.res.Worlds, w)
+	return w
+}
+
+// AddTypeDef adds a [TypeDef] named name of kind to owner, which is an [*Interface] or [*World].
+// A type added to a World is imported by the World.
+// If owner is nil, name must be empty, and an anonymous type is added to the Resolve.
+func (b *ResolveBuilder) AddTypeDef(owner TypeOwner, name string, kind TypeDefKind) *TypeDef {
+	if b.err != nil {
+		return nil
+	}
+	t := &TypeDef{Kind: kind}
+	switch owner := owner.(type) {
+	case nil:
+		if name != "" {
+			b.errorf("type %s has no owner", name)
+			return nil
+		}
+	case *Interface:
+		if _, ok := owner.TypeDefs.GetOK(name); ok {
+			b.errorf("duplicate type %s in interface %s", name, interfaceName(owner))
+			return nil
+		}
+		t.Name, t.Owner = &name, owner
+		owner.TypeDefs.Set(name, t)
+	case *World:
+		if _, ok := owner.Imports.GetOK(name); ok {
+			b.errorf("duplicate import %s in world %s", name, worldName(owner))
+			return nil
+		}
+		t.Name, t.Owner = &name, owner
+		owner.Imports.Set(name, t)
+	}
+	b.res.TypeDefs = append(b.res.TypeDefs, t)
+	return t
+}
+
+// AddRecord adds a [Record] type named name with fields to owner. See [ResolveBuilder.AddTypeDef].
+func (b *ResolveBuilder) AddRecord(owner TypeOwner, name string, fields ...Field) *TypeDef {
+	return b.AddTypeDef(owner, name, &Record{Fields: fields})
+}
+
+// AddFunction adds a freestanding [Function] named name with params and results to [Interface] i.
+// To add a function to a [World], use [ResolveBuilder.AddImport] or [ResolveBuilder.AddExport].
+func (b *ResolveBuilder) AddFunction(i *Interface, name string, params []Param, results []Param) *Function {
+	if b.err != nil {
+		return nil
+	}
+	if _, ok := i.Functions.GetOK(name); ok {
+		b.errorf("duplicate function %s in interface %s", name, interfaceName(i))
+		return nil
+	}
+	f := &Function{Name: name, Kind: &Freestanding{}, Params: params, Results: results}
+	i.Functions.Set(name, f)
+	return f
+}
+
+// AddImport adds item, an [*InterfaceRef], [*TypeDef], or [*Function], to the imports of [World] w.
+// An InterfaceRef to a named [Interface] is imported by its qualified name, e.g. "wasi:io/streams";
+// otherwise item is imported as name.
+func (b *ResolveBuilder) AddImport(w *World, name string, item WorldItem) {
+	b.addWorldItem(w, "import", name, item)
+}
+
+// AddExport adds item, an [*InterfaceRef] or [*Function], to the exports of [World] w.
+// See [ResolveBuilder.AddImport].
+func (b *ResolveBuilder) AddExport(w *World, name string, item WorldItem) {
+	b.addWorldItem(w, "export", name, item)
+}
+
+func (b *ResolveBuilder) addWorldItem(w *World, direction, name string, item WorldItem) {
+	if b.err != nil {
+		return
+	}
+	items := &w.Imports
+	if direction == "export" {
+		items = &w.Exports
+	}
+	if ref, ok := item.(*InterfaceRef); ok && ref.Interface.Name != nil {
+		name = interfaceName(ref.Interface)
+	}
+	if _, ok := items.GetOK(name); ok {
+		b.errorf("duplicate %s %s in world %s", direction, name, worldName(w))
+		return
+	}
+	items.Set(name, item)
+}
+
+// Build returns the [Resolve] built by [ResolveBuilder] b, or the first error encountered.
+// Anonymous types referred to by the types and functions in the Resolve, such as list<u8>,
+// need not be added with [ResolveBuilder.AddTypeDef]; Build adds them to the TypeDefs of the Resolve.
+// TypeDefs is ordered so each type follows the types it refers to, otherwise preserving the
+// order types were added. The Resolve is then checked with [Resolve.Validate].
+func (b *ResolveBuilder) Build() (*Resolve, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	n := &normalizer{
+		interfaces: make(map[*Interface]bool),
+		types:      make(map[*TypeDef]bool),
+	}
+	for _, t := range b.res.TypeDefs {
+		n.visitType(t)
+	}
+	b.res.AllFunctions()(func(f *Function) bool {
+		n.visitFunction(f)
+		return true
+	})
+	b.res.TypeDefs = n.sortedTypes
+	if err := b.res.Validate(); err != nil {
+		return nil, err
+	}
+	return b.res, nil
+}
+
+// FunctionSig describes a freestanding function for [StubWorld].
+type FunctionSig struct {
+	Name    string
+	Params  []Param
+	Results []Param
+
+	// Export is true if the function is exported by the world, otherwise it is imported.
+	Export bool
+}
+
+// StubWorld returns a [World] named name that imports or exports the functions fns,
+// with no other imports or exports, built with a [ResolveBuilder] into a new [Resolve].
+// It is intended for tests that need a small, controlled world without writing WIT.
+//
+// If name is a qualified world name, e.g. "test:stub/w@0.1.0", the world belongs to that package.
+// Otherwise, the world belongs to a package named "stub:stub".
+// StubWorld returns an error if name or any function is invalid, e.g. if two functions have the same name.
+func StubWorld(name string, fns ...FunctionSig) (*World, error) {
+	pkg := "stub:stub"
+	if id, err := ParseIdent(name); err == nil && id.Extension != "" {
+		name = id.Extension
+		id.Extension = ""
+		pkg = id.String()
+	}
+	b := NewResolveBuilder()
+	w := b.AddWorld(b.AddPackage(pkg), name)
+	for _, sig := range fns {
+		f := &Function{Name: sig.Name, Kind: &Freestanding{}, Params: sig.Params, Results: sig.Results}
+		if sig.Export {
+			b.AddExport(w, sig.Name, f)
+		} else {
+			b.AddImport(w, sig.Name, f)
+		}
+	}
+	if _, err := b.Build(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
@@ -0,0 +1,132 @@
+package wit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveBuilder(t *testing.T) {
+	b := NewResolveBuilder()
+	p := b.AddPackage("wasi:clocks@0.2.0")
+	i := b.AddInterface(p, "wall-clock")
+	datetime := b.AddRecord(i, "datetime",
+		Field{Name: "seconds", Type: U64{}},
+		Field{Name: "nanoseconds", Type: U32{}},
+	)
+	b.AddFunction(i, "now", nil, []Param{{Type: datetime}})
+	b.AddFunction(i, "history", nil, []Param{{Type: &TypeDef{Kind: &List{Type: datetime}}}})
+	w := b.AddWorld(p, "imports")
+	b.AddImport(w, "", &InterfaceRef{Interface: i})
+	b.AddExport(w, "run", &Function{Name: "run", Kind: &Freestanding{}})
+	res, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `package wasi:clocks@0.2.0;
+
+interface wall-clock {
+	record datetime {
+		seconds: u64,
+		nanoseconds: u32,
+	}
+	now: func() -> datetime;
+	history: func() -> list<datetime>;
+}
+
+world imports {
+	import wall-clock;
+	export run: func();
+}
+`
+	if got := res.WIT(nil, ""); got != want {
+		t.Errorf("WIT():\n%s\nexpected:\n%s", got, want)
+	}
+	if datetime.Owner != i || i.Package != p || w.Package != p {
+		t.Errorf("Build(): owners not linked")
+	}
+	if len(res.TypeDefs) != 2 || res.TypeDefs[0] != datetime {
+		t.Errorf("TypeDefs: %d types, expected datetime followed by list<datetime>", len(res.TypeDefs))
+	}
+}
+
+func TestResolveBuilderErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(b *ResolveBuilder)
+		want  string
+	}{
+		{"invalid package", func(b *ResolveBuilder) { b.AddPackage("wasi") }, "package wasi"},
+		{"duplicate package", func(b *ResolveBuilder) {
+			b.AddPackage("wasi:io")
+			b.AddPackage("wasi:io")
+		}, "duplicate package wasi:io"},
+		{"duplicate interface", func(b *ResolveBuilder) {
+			p := b.AddPackage("wasi:io")
+			b.AddInterface(p, "streams")
+			b.AddInterface(p, "streams")
+		}, "duplicate interface streams"},
+		{"duplicate type", func(b *ResolveBuilder) {
+			i := b.AddInterface(b.AddPackage("wasi:io"), "streams")
+			b.AddRecord(i, "r")
+			b.AddRecord(i, "r")
+		}, "duplicate type r"},
+		{"named type without owner", func(b *ResolveBuilder) {
+			b.AddTypeDef(nil, "t", U32{})
+		}, "type t has no owner"},
+		{"duplicate export", func(b *ResolveBuilder) {
+			w := b.AddWorld(b.AddPackage("wasi:io"), "w")
+			b.AddExport(w, "f", &Function{Name: "f", Kind: &Freestanding{}})
+			b.AddExport(w, "f", &Function{Name: "f", Kind: &Freestanding{}})
+		}, "duplicate export f"},
+		{"invalid handle", func(b *ResolveBuilder) {
+			i := b.AddInterface(b.AddPackage("wasi:io"), "streams")
+			r := b.AddRecord(i, "r")
+			b.AddTypeDef(i, "h", &Own{Type: r})
+		}, "resource"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewResolveBuilder()
+			tt.build(b)
+			_, err := b.Build()
+			if err == nil {
+				t.Fatalf("Build(): expected error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Build(): %v, expected error containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestStubWorld(t *testing.T) {
+	w, err := StubWorld("test:stub/w@0.1.0",
+		FunctionSig{Name: "log", Params: []Param{{Name: "msg", Type: String{}}}},
+		FunctionSig{Name: "run", Results: []Param{{Type: &TypeDef{Kind: &List{Type: U8{}}}}}, Export: true},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `world w {
+	import log: func(msg: string);
+	export run: func() -> list<u8>;
+}`
+	if got := w.WIT(nil, "w"); got != want {
+		t.Errorf("WIT():\n%s\nexpected:\n%s", got, want)
+	}
+	if got, want := w.Package.Name.String(), "test:stub@0.1.0"; got != want {
+		t.Errorf("Package: %s, expected %s", got, want)
+	}
+	w, err = StubWorld("w")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := w.Package.Name.String(), "stub:stub"; got != want {
+		t.Errorf("Package: %s, expected %s", got, want)
+	}
+
+	if _, err := StubWorld("w", FunctionSig{Name: "f"}, FunctionSig{Name: "f"}); err == nil {
+		t.Error("StubWorld with duplicate functions: expected error, got nil")
+	}
+}
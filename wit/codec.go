@@ -1,7 +1,11 @@
 package wit
 
 import (
+	"bytes"
+	stdjson "encoding/json"
+	"fmt"
 	"io"
+	"io/fs"
 
 	"github.com/coreos/go-semver/semver"
 	"go.bytecodealliance.org/internal/codec"
@@ -9,14 +13,149 @@ import (
 )
 
 // DecodeJSON decodes JSON from r into a [Resolve] struct.
-// It returns any error that may occur during decoding.
-func DecodeJSON(r io.Reader) (*Resolve, error) {
+// It returns any error that may occur during decoding, wrapped in [ErrDecodeFailed].
+//
+// DecodeJSON reads r incrementally, one JSON token at a time, without buffering the
+// entire document or an intermediate representation in memory. References by index
+// to worlds, interfaces, types, and packages are resolved as they are decoded,
+// allocating the referenced value on first use, so no separate fixup pass is required.
+// The [RetainRawJSON] option disables this, buffering the entire document.
+func DecodeJSON(r io.Reader, opts ...DecodeOption) (*Resolve, error) {
+	var o decodeOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.progress != nil {
+		r = newProgressReader(r, o.progress)
+	}
+	var data []byte
+	if o.retainRawJSON {
+		var err error
+		data, err = io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(data)
+	}
+
 	res := &Resolve{}
 	dec := json.NewDecoder(r, res)
 	err := dec.Decode(res)
+	if err != nil {
+		return res, fmt.Errorf("%w: %w", ErrDecodeFailed, err)
+	}
+	if res.errorContext != nil {
+		res.TypeDefs = append(res.TypeDefs, res.errorContext)
+		res.errorContext = nil
+	}
+	if o.retainRawJSON {
+		err = res.retainRawJSON(data)
+		if err != nil {
+			err = fmt.Errorf("%w: %w", ErrDecodeFailed, err)
+		}
+	}
 	return res, err
 }
 
+// DecodeOption represents an option for [DecodeJSON].
+type DecodeOption func(*decodeOptions)
+
+type decodeOptions struct {
+	retainRawJSON bool
+	progress      ProgressFunc
+}
+
+// RetainRawJSON returns a [DecodeOption] that retains the JSON for each world, interface,
+// type, and package decoded by [DecodeJSON], accessible with [Resolve.RawJSON].
+// This is intended as a debugging aid, e.g. to correlate a decoded item with its source.
+// It requires buffering the entire JSON document in memory.
+func RetainRawJSON() DecodeOption {
+	return func(o *decodeOptions) {
+		o.retainRawJSON = true
+	}
+}
+
+// ProgressFunc is called by [DecodeJSON] to report progress, where decoded is the number of
+// bytes of JSON read so far, and total is the size of the JSON in bytes, or -1 if unknown.
+type ProgressFunc func(decoded, total int)
+
+// DecodeProgress returns a [DecodeOption] that calls f periodically while decoding,
+// each time [DecodeJSON] reads a chunk of JSON from its input, and once more when the
+// input is exhausted. The total size is known if the input is an [*os.File], or implements
+// a Len method that reports the number of unread bytes, like [*bytes.Reader] or [*strings.Reader].
+func DecodeProgress(f ProgressFunc) DecodeOption {
+	return func(o *decodeOptions) {
+		o.progress = f
+	}
+}
+
+// progressReader is an [io.Reader] that reports the number of bytes read to a [ProgressFunc].
+type progressReader struct {
+	r        io.Reader
+	f        ProgressFunc
+	total    int
+	n        int
+	finished bool
+}
+
+func newProgressReader(r io.Reader, f ProgressFunc) *progressReader {
+	total := -1
+	switch v := r.(type) {
+	case interface{ Len() int }:
+		total = v.Len()
+	case interface{ Stat() (fs.FileInfo, error) }:
+		if fi, err := v.Stat(); err == nil && fi.Mode().IsRegular() {
+			total = int(fi.Size())
+		}
+	}
+	return &progressReader{r: r, f: f, total: total}
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.n += n
+	if n > 0 || (err == io.EOF && !p.finished) {
+		p.finished = err == io.EOF
+		p.f(p.n, p.total)
+	}
+	return n, err
+}
+
+// RawJSON returns the JSON that item, a [*World], [*Interface], [*TypeDef], or [*Package],
+// was decoded from, if [Resolve] r was decoded by [DecodeJSON] with the [RetainRawJSON] option.
+// It returns false if item was not decoded from JSON into r.
+func (r *Resolve) RawJSON(item any) (stdjson.RawMessage, bool) {
+	msg, ok := r.raw[item]
+	return msg, ok
+}
+
+// retainRawJSON associates each top-level item in r with its JSON in data.
+// Items are matched by their index in each top-level array.
+func (r *Resolve) retainRawJSON(data []byte) error {
+	var raw struct {
+		Worlds     []stdjson.RawMessage `json:"worlds"`
+		Interfaces []stdjson.RawMessage `json:"interfaces"`
+		TypeDefs   []stdjson.RawMessage `json:"types"`
+		Packages   []stdjson.RawMessage `json:"packages"`
+	}
+	err := stdjson.Unmarshal(data, &raw)
+	if err != nil {
+		return err
+	}
+	r.raw = make(map[any]stdjson.RawMessage)
+	retainRaw(r.raw, r.Worlds, raw.Worlds)
+	retainRaw(r.raw, r.Interfaces, raw.Interfaces)
+	retainRaw(r.raw, r.TypeDefs, raw.TypeDefs)
+	retainRaw(r.raw, r.Packages, raw.Packages)
+	return nil
+}
+
+func retainRaw[E any](m map[any]stdjson.RawMessage, items []*E, raw []stdjson.RawMessage) {
+	for i := range min(len(items), len(raw)) {
+		m[items[i]] = raw[i]
+	}
+}
+
 // ResolveCodec implements the [codec.Resolver] interface
 // translating types to decoding/encoding-aware versions.
 func (res *Resolve) ResolveCodec(v any) codec.Codec {
@@ -93,6 +232,9 @@ func (c *Resolve) DecodeField(dec codec.Decoder, name string) error {
 		return codec.DecodeSlice(dec, &c.TypeDefs)
 	case "packages":
 		return codec.DecodeSlice(dec, &c.Packages)
+	case "features":
+		c.ActiveFeatures = []string{} // non-nil if present, even if empty
+		return codec.DecodeSlice(dec, &c.ActiveFeatures)
 	}
 	return nil
 }
@@ -252,6 +394,25 @@ func (d *Docs) DecodeField(dec codec.Decoder, name string) error {
 	return nil
 }
 
+// MarshalJSON implements [encoding/json.Marshaler], encoding [Docs] d
+// in the same shape as wasm-tools: an object with a contents field,
+// which is null if d is empty.
+func (d Docs) MarshalJSON() ([]byte, error) {
+	var v struct {
+		Contents *string `json:"contents"`
+	}
+	if d.Contents != "" {
+		v.Contents = &d.Contents
+	}
+	var b bytes.Buffer
+	enc := stdjson.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), nil
+}
+
 // worldItemCodec translates typed WorldItem references into a WorldItem,
 // currently either an Interface or a TypeDef.
 type worldItemCodec struct {
@@ -284,7 +445,17 @@ type typeCodec struct {
 }
 
 // DecodeString translates s into to a primitive WIT type.
+// Newer versions of wasm-tools encode error-context as a primitive type,
+// which is decoded as an anonymous [TypeDef] of kind [ErrorContext], shared by
+// all uses in the [Resolve].
 func (c *typeCodec) DecodeString(s string) error {
+	if s == "error-context" {
+		if c.errorContext == nil {
+			c.errorContext = &TypeDef{Kind: &ErrorContext{}}
+		}
+		*c.t = c.errorContext
+		return nil
+	}
 	var err error
 	*c.t, err = ParseType(s)
 	return err
@@ -324,6 +495,8 @@ func (c *typeDefKindCodec) DecodeString(s string) error {
 	switch s {
 	case "resource":
 		*c.v = &Resource{}
+	case "error-context":
+		*c.v = &ErrorContext{}
 	}
 	return nil
 }
@@ -591,7 +764,11 @@ func (f *Function) DecodeField(dec codec.Decoder, name string) error {
 	case "params":
 		return codec.DecodeSlice(dec, &f.Params)
 	case "results":
-		return codec.DecodeSlice(dec, &f.Results)
+		err := codec.DecodeSlice(dec, &f.Results)
+		if err != nil {
+			return err
+		}
+		return f.validateResults()
 	case "stability":
 		return dec.Decode(&f.Stability)
 	case "docs":
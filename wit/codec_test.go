@@ -0,0 +1,470 @@
+package wit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go.bytecodealliance.org/wit/ordered"
+)
+
+func TestDecodeFunctionResults(t *testing.T) {
+	tests := []struct {
+		name    string
+		results string
+		wantErr bool
+	}{
+		{"none", `[]`, false},
+		{"anonymous", `[{"type": "u32"}]`, false},
+		{"named", `[{"name": "a", "type": "u32"}]`, false},
+		{"multiple named", `[{"name": "a", "type": "u32"}, {"name": "b", "type": "string"}]`, false},
+		{"mixed", `[{"name": "a", "type": "u32"}, {"type": "string"}]`, true},
+		{"multiple anonymous", `[{"type": "u32"}, {"type": "string"}]`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := `{"worlds": [{"name": "w", "imports": {"f": {"function": {"name": "f", "kind": "freestanding", "params": [], "results": ` + tt.results + `}}}, "exports": {}}]}`
+			_, err := DecodeJSON(strings.NewReader(data))
+			if tt.wantErr && err == nil {
+				t.Errorf("DecodeJSON: expected error, got nil error")
+			} else if !tt.wantErr && err != nil {
+				t.Errorf("DecodeJSON: expected no error, got error: %v", err)
+			}
+		})
+	}
+}
+
+func TestDocsRoundTrip(t *testing.T) {
+	tests := []string{
+		`{"contents":null}`,
+		`{"contents":"One line."}`,
+		`{"contents":"First line.\n\nSecond paragraph with list<u8> & \"quotes\".\n\tIndented\\path"}`,
+		`{"contents":"Unicode: héllo, 世界, 🦀"}`,
+	}
+	for _, docs := range tests {
+		t.Run(docs, func(t *testing.T) {
+			res, err := DecodeJSON(strings.NewReader(`{"interfaces":[{"name":"i","types":{},"functions":{},"docs":` + docs + `,"package":0}],"packages":[{"name":"a:b","interfaces":{"i":0},"worlds":{}}]}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var b bytes.Buffer
+			enc := json.NewEncoder(&b)
+			enc.SetEscapeHTML(false) // match wasm-tools, which does not escape <, >, or &
+			if err := enc.Encode(&res.Interfaces[0].Docs); err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.TrimSuffix(b.String(), "\n"); got != docs {
+				t.Errorf("round trip:\n%s\nexpected:\n%s", got, docs)
+			}
+
+			// Docs is encoded the same way when it is a value field of another type.
+			b.Reset()
+			if err := enc.Encode(struct{ Docs Docs }{res.Interfaces[0].Docs}); err != nil {
+				t.Fatal(err)
+			}
+			if got, want := strings.TrimSuffix(b.String(), "\n"), `{"Docs":`+docs+`}`; got != want {
+				t.Errorf("value field:\n%s\nexpected:\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestDecodeDeclarationOrder(t *testing.T) {
+	data := `{
+	"worlds": [
+		{
+			"name": "w",
+			"imports": {
+				"z": {"function": {"name": "z", "kind": "freestanding", "params": [], "results": []}},
+				"interface-1": {"interface": {"id": 1}},
+				"a": {"function": {"name": "a", "kind": "freestanding", "params": [], "results": []}}
+			},
+			"exports": {
+				"interface-0": {"interface": {"id": 0}},
+				"m": {"function": {"name": "m", "kind": "freestanding", "params": [], "results": []}}
+			},
+			"package": 0
+		}
+	],
+	"interfaces": [
+		{
+			"name": "zz",
+			"types": {"z": 0, "a": 1, "m": 2},
+			"functions": {
+				"z": {"name": "z", "kind": "freestanding", "params": [], "results": []},
+				"a": {"name": "a", "kind": "freestanding", "params": [], "results": []}
+			},
+			"package": 0
+		},
+		{"name": "aa", "types": {}, "functions": {}, "package": 0}
+	],
+	"types": [
+		{"name": "z", "kind": {"type": "u32"}, "owner": {"interface": 0}},
+		{"name": "a", "kind": {"type": "u32"}, "owner": {"interface": 0}},
+		{"name": "m", "kind": {"type": "u32"}, "owner": {"interface": 0}}
+	],
+	"packages": [
+		{"name": "foo:bar", "interfaces": {"zz": 0, "aa": 1}, "worlds": {"w": 0}}
+	]
+}`
+	res, err := DecodeJSON(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg := res.Packages[0]
+	i := pkg.Interfaces.Get("zz")
+	w := pkg.Worlds.Get("w")
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"Package.Interfaces", orderedKeys(&pkg.Interfaces), "zz aa"},
+		{"Interface.TypeDefs", orderedKeys(&i.TypeDefs), "z a m"},
+		{"Interface.Functions", orderedKeys(&i.Functions), "z a"},
+		{"World.Imports", orderedKeys(&w.Imports), "z interface-1 a"},
+		{"World.Exports", orderedKeys(&w.Exports), "interface-0 m"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: %q, expected %q", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func orderedKeys[V any](m *ordered.Map[string, V]) string {
+	var keys []string
+	m.All()(func(k string, _ V) bool {
+		keys = append(keys, k)
+		return true
+	})
+	return strings.Join(keys, " ")
+}
+
+func TestDecodeStability(t *testing.T) {
+	res, err := LoadJSON(filepath.Join(testdataPath, "wit-parser/since-and-unstable.wit.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg := res.Packages[0]
+	iface := pkg.Interfaces.Get("in-an-interface")
+	deprecated := pkg.Interfaces.Get("deprecated1")
+	world := pkg.Worlds.Get("in-a-world")
+	tests := []struct {
+		name string
+		s    Stability
+		want string
+	}{
+		{"interface foo1", pkg.Interfaces.Get("foo1").Stability, "@since(version = 1.0.0)"},
+		{"interface foo4", pkg.Interfaces.Get("foo4").Stability, "@unstable(feature = foo2)"},
+		{"world w1", pkg.Worlds.Get("w1").Stability, "@since(version = 1.0.1)"},
+		{"function foo", iface.Functions.Get("foo").Stability, "@since(version = 1.0.0)"},
+		{"type t1", iface.TypeDefs.Get("t1").Stability, "@since(version = 1.0.0)"},
+		{"deprecated type t1", deprecated.TypeDefs.Get("t1").Stability, "@since(version = 1.0.0)\n@deprecated(version = 1.0.1)"},
+		{"deprecated type t3", deprecated.TypeDefs.Get("t3").Stability, "@unstable(feature = foo)\n@deprecated(version = 1.0.1)"},
+		{"world function x", world.Imports.Get("x").(*Function).Stability, "@since(version = 1.0.0)"},
+		{"world interface z", world.Imports.Get("interface-5").(*InterfaceRef).Stability, "@since(version = 1.0.0)"},
+		{"interface z", pkg.Interfaces.Get("z").Stability, ""},
+	}
+	for _, tt := range tests {
+		var got string
+		if tt.s != nil {
+			got = tt.s.WIT(nil, "")
+		}
+		if got != tt.want {
+			t.Errorf("%s: Stability %q, expected %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func BenchmarkDecodeJSON(b *testing.B) {
+	for _, name := range []string{"wasi/cli.wit.json", "wasi/http.wit.json"} {
+		data, err := os.ReadFile(filepath.Join(testdataPath, name))
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				_, err := DecodeJSON(bytes.NewReader(data))
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkDecodeJSONLarge decodes a synthetic bundle of roughly 20 MB, comparing
+// the default streaming decoder with [RetainRawJSON], which buffers the entire document.
+func BenchmarkDecodeJSONLarge(b *testing.B) {
+	data := largeJSON(70_000)
+	for _, tt := range []struct {
+		name string
+		opts []DecodeOption
+	}{
+		{"stream", nil},
+		{"buffered", []DecodeOption{RetainRawJSON()}},
+	} {
+		b.Run(tt.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				_, err := DecodeJSON(bytes.NewReader(data), tt.opts...)
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// largeJSON returns the JSON for a package with one interface containing n record types,
+// each with a field that refers to the previous type.
+func largeJSON(n int) []byte {
+	var b bytes.Buffer
+	b.WriteString(`{"worlds":[],"interfaces":[{"name":"i","types":{`)
+	for i := range n {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `"r%d":%d`, i, i)
+	}
+	b.WriteString(`},"functions":{},"package":0}],"types":[`)
+	for i := range n {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		prev := `"u32"`
+		if i > 0 {
+			prev = strconv.Itoa(i - 1)
+		}
+		fmt.Fprintf(&b, `{"name":"r%d","kind":{"record":{"fields":[`, i)
+		fmt.Fprintf(&b, `{"name":"a","type":"u64","docs":{"contents":"Field a of record r%d."}},`, i)
+		fmt.Fprintf(&b, `{"name":"b","type":"string"},{"name":"prev","type":%s}]}},`, prev)
+		b.WriteString(`"owner":{"interface":0},"docs":{"contents":"A synthetic record type for benchmarking."}}`)
+	}
+	b.WriteString(`],"packages":[{"name":"foo:bar","interfaces":{"i":0},"worlds":{}}]}`)
+	return b.Bytes()
+}
+
+func TestDecodeProgress(t *testing.T) {
+	const path = "../testdata/wasi/http.wit.json"
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	size := int(fi.Size())
+
+	tests := []struct {
+		name  string
+		open  func(f *os.File) io.Reader
+		total int
+	}{
+		{"file", func(f *os.File) io.Reader { return f }, size},
+		{"unknown size", func(f *os.File) io.Reader { return io.MultiReader(f) }, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			var calls, last int
+			_, err = DecodeJSON(tt.open(f), DecodeProgress(func(decoded, total int) {
+				calls++
+				if total != tt.total {
+					t.Errorf("total: %d, expected %d", total, tt.total)
+				}
+				if decoded < last {
+					t.Errorf("decoded: %d, expected >= %d", decoded, last)
+				}
+				last = decoded
+			}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if calls < 2 {
+				t.Errorf("ProgressFunc called %d time(s), expected more than once", calls)
+			}
+			if last != size {
+				t.Errorf("decoded: %d after DecodeJSON, expected %d", last, size)
+			}
+		})
+	}
+}
+
+func TestDecodeRetainRawJSON(t *testing.T) {
+	data := `{
+	"worlds": [{"name": "w", "imports": {}, "exports": {}, "package": 0}],
+	"interfaces": [{"name": "i", "types": {"t": 0}, "functions": {}, "package": 0}],
+	"types": [{"name": "t", "kind": {"type": "u32"}, "owner": {"interface": 0}}],
+	"packages": [{"name": "foo:bar", "interfaces": {"i": 0}, "worlds": {"w": 0}}]
+}`
+	res, err := DecodeJSON(strings.NewReader(data), RetainRawJSON())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		item any
+		want string
+	}{
+		{res.Worlds[0], `{"name": "w", "imports": {}, "exports": {}, "package": 0}`},
+		{res.Interfaces[0], `{"name": "i", "types": {"t": 0}, "functions": {}, "package": 0}`},
+		{res.TypeDefs[0], `{"name": "t", "kind": {"type": "u32"}, "owner": {"interface": 0}}`},
+		{res.Packages[0], `{"name": "foo:bar", "interfaces": {"i": 0}, "worlds": {"w": 0}}`},
+	}
+	for _, tt := range tests {
+		got, ok := res.RawJSON(tt.item)
+		if !ok {
+			t.Errorf("RawJSON(%T): not found", tt.item)
+		} else if string(got) != tt.want {
+			t.Errorf("RawJSON(%T): %s, expected %s", tt.item, got, tt.want)
+		}
+	}
+	if _, ok := res.RawJSON(&World{}); ok {
+		t.Errorf("RawJSON: expected false for item not in Resolve")
+	}
+
+	res, err = DecodeJSON(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := res.RawJSON(res.Worlds[0]); ok {
+		t.Errorf("RawJSON: expected false without RetainRawJSON")
+	}
+}
+
+func TestDecodeActiveFeatures(t *testing.T) {
+	tests := []struct {
+		name string
+		json string
+		want []string
+	}{
+		{"absent", `{"worlds": [], "interfaces": [], "types": [], "packages": []}`, nil},
+		{"empty", `{"worlds": [], "interfaces": [], "types": [], "packages": [], "features": []}`, []string{}},
+		{"features", `{"worlds": [], "interfaces": [], "types": [], "packages": [], "features": ["foo", "bar"]}`, []string{"foo", "bar"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := DecodeJSON(strings.NewReader(tt.json))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (res.ActiveFeatures == nil) != (tt.want == nil) || !slices.Equal(res.ActiveFeatures, tt.want) {
+				t.Errorf("ActiveFeatures: %#v, expected %#v", res.ActiveFeatures, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeInlineInterface(t *testing.T) {
+	data := `{
+	"worlds": [{
+		"name": "w",
+		"imports": {"foo": {"interface": {"id": 0}}},
+		"exports": {},
+		"package": 0
+	}],
+	"interfaces": [{
+		"name": null,
+		"types": {"point": 0},
+		"functions": {
+			"get": {"name": "get", "kind": "freestanding", "params": [], "results": [{"type": 0}]}
+		},
+		"package": 0
+	}],
+	"types": [{
+		"name": "point",
+		"kind": {"record": {"fields": [{"name": "x", "type": "u32"}]}},
+		"owner": {"interface": 0}
+	}],
+	"packages": [{"name": "foo:bar", "interfaces": {}, "worlds": {"w": 0}}]
+}`
+	res, err := DecodeJSON(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := res.Worlds[0]
+	ref, ok := w.Imports.Get("foo").(*InterfaceRef)
+	if !ok {
+		t.Fatalf("import foo: %T, expected *InterfaceRef", w.Imports.Get("foo"))
+	}
+	i := ref.Interface
+	if i.Name != nil {
+		t.Errorf("inline interface Name: %q, expected nil", *i.Name)
+	}
+	if i.Package != res.Packages[0] {
+		t.Errorf("inline interface Package: %v, expected %s", i.Package, res.Packages[0].Name.String())
+	}
+	if res.Packages[0].Interfaces.Len() != 0 {
+		t.Errorf("inline interface is listed in package interfaces")
+	}
+	if i.Match("foo") {
+		t.Errorf("Match(%q): true, expected false for inline interface", "foo")
+	}
+
+	var names []string
+	w.AllInterfaces()(func(name string, face *Interface) bool {
+		if face != i {
+			t.Errorf("AllInterfaces: unexpected interface %s", name)
+		}
+		names = append(names, name)
+		return true
+	})
+	if want := []string{"foo"}; !slices.Equal(names, want) {
+		t.Errorf("AllInterfaces: %v, expected %v", names, want)
+	}
+	if imports := w.ImportedInterfaces(); len(imports) != 1 || imports[0].Name != "foo" || imports[0].Interface != i {
+		t.Errorf("ImportedInterfaces: %v, expected foo", imports)
+	}
+
+	point := i.TypeDefs.Get("point")
+	if point == nil || point.Owner != i || point != res.TypeDefs[0] {
+		t.Errorf("type point: not owned by inline interface")
+	}
+	if name := point.QualifiedName(); name != "" {
+		t.Errorf("QualifiedName(): %q, expected empty for type in inline interface", name)
+	}
+	get := i.Functions.Get("get")
+	if get == nil || len(get.Results) != 1 || get.Results[0].Type != point {
+		t.Fatalf("function get: results not decoded")
+	}
+	var found bool
+	res.AllFunctions()(func(f *Function) bool {
+		found = f == get
+		return !found
+	})
+	if !found {
+		t.Errorf("AllFunctions: function in inline interface not found")
+	}
+
+	want := `package foo:bar;
+
+world w {
+	import foo: interface {
+		record point { x: u32 }
+		get: func() -> point;
+	}
+}
+`
+	if got := res.WIT(nil, ""); got != want {
+		t.Errorf("WIT:\n%s\nexpected:\n%s", got, want)
+	}
+	parsed, err := ParseWIT(strings.NewReader(want))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := parsed.WIT(nil, ""); got != want {
+		t.Errorf("WIT after ParseWIT:\n%s\nexpected:\n%s", got, want)
+	}
+}
@@ -0,0 +1,425 @@
+package wit
+
+import (
+	"reflect"
+
+	"go.bytecodealliance.org/wit/ordered"
+)
+
+// ChangeKind describes how an item differs between two versions of a [Resolve].
+type ChangeKind int
+
+const (
+	// Added indicates an item present only in the new Resolve.
+	Added ChangeKind = iota
+
+	// Removed indicates an item present only in the old Resolve.
+	Removed
+
+	// Modified indicates an item present in both with a different definition.
+	Modified
+)
+
+// String implements [fmt.Stringer], returning "added", "removed", or "modified".
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Modified:
+		return "modified"
+	}
+	return "<unknown ChangeKind>"
+}
+
+// Change describes a single difference between two versions of a [Resolve], as reported by [Diff].
+type Change struct {
+	Kind ChangeKind
+
+	// Name is the unversioned name of the changed item, for example
+	// "wasi:clocks/wall-clock" for an interface, "wasi:clocks/wall-clock#now"
+	// for a function or type in an interface, or "wasi:cli/command import wasi:cli/stdin"
+	// for an import or export of a world.
+	Name string
+
+	// Old and New are the item in the old and new Resolve.
+	// Old is nil if the item was added, and New is nil if the item was removed.
+	Old, New Node
+
+	// Breaking reports whether the change is incompatible with users of the old Resolve.
+	// Breaking is conservative: every Modified change is breaking, as are Removed changes
+	// and exports added to a world. Diff does not check whether a modification is compatible,
+	// such as adding a case to an enum or a field to a record, as any change to a type or
+	// function changes its Canonical ABI or its generated bindings.
+	Breaking bool
+}
+
+// String returns a human-readable description of [Change] c,
+// e.g. "removed function wasi:clocks/wall-clock#now (breaking)".
+func (c *Change) String() string {
+	node := c.New
+	if node == nil {
+		node = c.Old
+	}
+	kind := node.WITKind()
+	if _, ok := node.(*TypeDef); ok {
+		kind = "type"
+	}
+	s := c.Kind.String() + " " + kind + " " + c.Name
+	if c.Breaking {
+		s += " (breaking)"
+	}
+	return s
+}
+
+// Diff compares two versions of a [Resolve] and returns the worlds, world imports and exports,
+// interfaces, functions, and types that were added, removed, or modified from old to new.
+// Items are matched by name, ignoring package versions, so Diff can compare
+// successive versions of a package. Changes to documentation and stability are ignored.
+//
+// Removing or modifying an item is a breaking change, as is adding an export to a world,
+// which existing implementations of the world do not provide. Other additions are compatible.
+// Types are compared structurally, except that references to named types are compared by name,
+// so a change to a named type is reported once, for that type.
+func Diff(old, new *Resolve) []Change {
+	var d differ
+
+	// Worlds
+	oldWorlds, newWorlds := worldsByName(old), worldsByName(new)
+	for _, w := range old.Worlds {
+		name := worldName(w)
+		if nw, ok := newWorlds[name]; ok {
+			d.diffWorld(name, w, nw)
+		} else {
+			d.add(Change{Kind: Removed, Name: name, Old: w, Breaking: true})
+		}
+	}
+	for _, w := range new.Worlds {
+		if name := worldName(w); oldWorlds[name] == nil {
+			d.add(Change{Kind: Added, Name: name, New: w})
+		}
+	}
+
+	// Named interfaces
+	oldFaces, newFaces := interfacesByName(old), interfacesByName(new)
+	for _, i := range old.Interfaces {
+		name := unversionedInterfaceName(i)
+		if name == "" {
+			continue
+		}
+		if ni, ok := newFaces[name]; ok {
+			d.diffInterface(name, i, ni)
+		} else {
+			d.add(Change{Kind: Removed, Name: name, Old: i, Breaking: true})
+		}
+	}
+	for _, i := range new.Interfaces {
+		if name := unversionedInterfaceName(i); name != "" && oldFaces[name] == nil {
+			d.add(Change{Kind: Added, Name: name, New: i})
+		}
+	}
+
+	return d.changes
+}
+
+type differ struct {
+	changes []Change
+}
+
+func (d *differ) add(c Change) {
+	d.changes = append(d.changes, c)
+}
+
+func (d *differ) diffWorld(name string, old, new *World) {
+	d.diffWorldItems(name+" import ", &old.Imports, &new.Imports, false)
+	d.diffWorldItems(name+" export ", &old.Exports, &new.Exports, true)
+}
+
+func (d *differ) diffWorldItems(prefix string, old, new *ordered.Map[string, WorldItem], export bool) {
+	oldItems, newItems := worldItemsByName(old), worldItemsByName(new)
+	old.All()(func(key string, item WorldItem) bool {
+		name := worldItemName(key, item)
+		if ni, ok := newItems[name]; ok {
+			if !sameWorldItem(item, ni) {
+				d.add(Change{Kind: Modified, Name: prefix + name, Old: item, New: ni, Breaking: true})
+			}
+		} else {
+			d.add(Change{Kind: Removed, Name: prefix + name, Old: item, Breaking: true})
+		}
+		return true
+	})
+	new.All()(func(key string, item WorldItem) bool {
+		name := worldItemName(key, item)
+		if _, ok := oldItems[name]; !ok {
+			d.add(Change{Kind: Added, Name: prefix + name, New: item, Breaking: export})
+		}
+		return true
+	})
+}
+
+func (d *differ) diffInterface(name string, old, new *Interface) {
+	old.TypeDefs.All()(func(n string, t *TypeDef) bool {
+		if nt, ok := new.TypeDefs.GetOK(n); ok {
+			if !sameKind(t.Kind, nt.Kind) {
+				d.add(Change{Kind: Modified, Name: name + "#" + n, Old: t, New: nt, Breaking: true})
+			}
+		} else {
+			d.add(Change{Kind: Removed, Name: name + "#" + n, Old: t, Breaking: true})
+		}
+		return true
+	})
+	new.TypeDefs.All()(func(n string, t *TypeDef) bool {
+		if _, ok := old.TypeDefs.GetOK(n); !ok {
+			d.add(Change{Kind: Added, Name: name + "#" + n, New: t})
+		}
+		return true
+	})
+	old.Functions.All()(func(n string, f *Function) bool {
+		if nf, ok := new.Functions.GetOK(n); ok {
+			if !sameFunction(f, nf) {
+				d.add(Change{Kind: Modified, Name: name + "#" + n, Old: f, New: nf, Breaking: true})
+			}
+		} else {
+			d.add(Change{Kind: Removed, Name: name + "#" + n, Old: f, Breaking: true})
+		}
+		return true
+	})
+	new.Functions.All()(func(n string, f *Function) bool {
+		if _, ok := old.Functions.GetOK(n); !ok {
+			d.add(Change{Kind: Added, Name: name + "#" + n, New: f})
+		}
+		return true
+	})
+}
+
+func worldName(w *World) string {
+	id := w.Package.Name
+	id.Extension = w.Name
+	return id.UnversionedString()
+}
+
+func worldsByName(r *Resolve) map[string]*World {
+	m := make(map[string]*World, len(r.Worlds))
+	for _, w := range r.Worlds {
+		m[worldName(w)] = w
+	}
+	return m
+}
+
+// unversionedInterfaceName returns the unversioned name of [Interface] i,
+// or an empty string if i is anonymous.
+func unversionedInterfaceName(i *Interface) string {
+	if i.Name == nil || i.Package == nil {
+		return ""
+	}
+	id := i.Package.Name
+	id.Extension = *i.Name
+	return id.UnversionedString()
+}
+
+func interfacesByName(r *Resolve) map[string]*Interface {
+	m := make(map[string]*Interface, len(r.Interfaces))
+	for _, i := range r.Interfaces {
+		if name := unversionedInterfaceName(i); name != "" {
+			m[name] = i
+		}
+	}
+	return m
+}
+
+// worldItemName returns the name of a world import or export,
+// without a version if item is a reference to a named interface.
+func worldItemName(key string, item WorldItem) string {
+	if ref, ok := item.(*InterfaceRef); ok {
+		if name := unversionedInterfaceName(ref.Interface); name != "" {
+			return name
+		}
+	}
+	return key
+}
+
+func worldItemsByName(items *ordered.Map[string, WorldItem]) map[string]WorldItem {
+	m := make(map[string]WorldItem)
+	items.All()(func(key string, item WorldItem) bool {
+		m[worldItemName(key, item)] = item
+		return true
+	})
+	return m
+}
+
+// sameWorldItem reports whether world items a and b have the same definition.
+// References to named interfaces are equal if the interfaces have the same name;
+// differences within the interfaces are reported separately.
+func sameWorldItem(a, b WorldItem) bool {
+	switch a := a.(type) {
+	case *InterfaceRef:
+		b, ok := b.(*InterfaceRef)
+		if !ok {
+			return false
+		}
+		if a.Interface.Name != nil || b.Interface.Name != nil {
+			return unversionedInterfaceName(a.Interface) == unversionedInterfaceName(b.Interface)
+		}
+		return sameInlineInterface(a.Interface, b.Interface)
+	case *TypeDef:
+		b, ok := b.(*TypeDef)
+		return ok && sameKind(a.Kind, b.Kind)
+	case *Function:
+		b, ok := b.(*Function)
+		return ok && sameFunction(a, b)
+	}
+	return false
+}
+
+// sameInlineInterface reports whether anonymous interfaces a and b have the same types and functions.
+func sameInlineInterface(a, b *Interface) bool {
+	if a.TypeDefs.Len() != b.TypeDefs.Len() || a.Functions.Len() != b.Functions.Len() {
+		return false
+	}
+	same := true
+	a.TypeDefs.All()(func(n string, t *TypeDef) bool {
+		bt, ok := b.TypeDefs.GetOK(n)
+		same = ok && sameKind(t.Kind, bt.Kind)
+		return same
+	})
+	a.Functions.All()(func(n string, f *Function) bool {
+		bf, ok := b.Functions.GetOK(n)
+		same = same && ok && sameFunction(f, bf)
+		return same
+	})
+	return same
+}
+
+// sameFunction reports whether functions a and b have the same kind, parameters, and results.
+func sameFunction(a, b *Function) bool {
+	if a.Name != b.Name || reflect.TypeOf(a.Kind) != reflect.TypeOf(b.Kind) {
+		return false
+	}
+	return sameParams(a.Params, b.Params) && sameParams(a.Results, b.Results)
+}
+
+func sameParams(a, b []Param) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Name != b[i].Name || !sameType(a[i].Type, b[i].Type) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameType reports whether types a and b are equivalent. Named types are
+// equivalent if they have the same name and their owners have the same unversioned name.
+// Either a or b may be nil.
+func sameType(a, b Type) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	at, aok := a.(*TypeDef)
+	bt, bok := b.(*TypeDef)
+	if !aok || !bok {
+		return !aok && !bok && reflect.TypeOf(a) == reflect.TypeOf(b)
+	}
+	if at.Name != nil || bt.Name != nil {
+		return at.Name != nil && bt.Name != nil && *at.Name == *bt.Name && ownerName(at.Owner) == ownerName(bt.Owner)
+	}
+	return sameKind(at.Kind, bt.Kind)
+}
+
+// ownerName returns the unversioned name of a [World] or [Interface].
+func ownerName(owner TypeOwner) string {
+	switch owner := owner.(type) {
+	case *World:
+		return worldName(owner)
+	case *Interface:
+		return unversionedInterfaceName(owner)
+	}
+	return ""
+}
+
+// sameKind reports whether [TypeDefKind] a and b are structurally equivalent.
+func sameKind(a, b TypeDefKind) bool {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false
+	}
+	switch a := a.(type) {
+	case *TypeDef:
+		return sameType(a, b.(*TypeDef))
+	case *Record:
+		b := b.(*Record)
+		if len(a.Fields) != len(b.Fields) {
+			return false
+		}
+		for i := range a.Fields {
+			if a.Fields[i].Name != b.Fields[i].Name || !sameType(a.Fields[i].Type, b.Fields[i].Type) {
+				return false
+			}
+		}
+		return true
+	case *Variant:
+		b := b.(*Variant)
+		if len(a.Cases) != len(b.Cases) {
+			return false
+		}
+		for i := range a.Cases {
+			if a.Cases[i].Name != b.Cases[i].Name || !sameType(a.Cases[i].Type, b.Cases[i].Type) {
+				return false
+			}
+		}
+		return true
+	case *Enum:
+		b := b.(*Enum)
+		if len(a.Cases) != len(b.Cases) {
+			return false
+		}
+		for i := range a.Cases {
+			if a.Cases[i].Name != b.Cases[i].Name {
+				return false
+			}
+		}
+		return true
+	case *Flags:
+		b := b.(*Flags)
+		if len(a.Flags) != len(b.Flags) {
+			return false
+		}
+		for i := range a.Flags {
+			if a.Flags[i].Name != b.Flags[i].Name {
+				return false
+			}
+		}
+		return true
+	case *Tuple:
+		b := b.(*Tuple)
+		if len(a.Types) != len(b.Types) {
+			return false
+		}
+		for i := range a.Types {
+			if !sameType(a.Types[i], b.Types[i]) {
+				return false
+			}
+		}
+		return true
+	case *Option:
+		return sameType(a.Type, b.(*Option).Type)
+	case *List:
+		return sameType(a.Type, b.(*List).Type)
+	case *Result:
+		b := b.(*Result)
+		return sameType(a.OK, b.OK) && sameType(a.Err, b.Err)
+	case *Own:
+		return sameType(a.Type, b.(*Own).Type)
+	case *Borrow:
+		return sameType(a.Type, b.(*Borrow).Type)
+	case *Future:
+		return sameType(a.Type, b.(*Future).Type)
+	case *Stream:
+		b := b.(*Stream)
+		return sameType(a.Element, b.Element) && sameType(a.End, b.End)
+	}
+	// Resources and primitive types
+	return true
+}
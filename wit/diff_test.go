@@ -0,0 +1,92 @@
+package wit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	parse := func(s string) *Resolve {
+		t.Helper()
+		res, err := ParseWIT(strings.NewReader(s))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	old := parse(`package foo:bar@1.0.0;
+
+interface types {
+	/// A point.
+	record point { x: u32, y: u32 }
+	enum color { red, green }
+	type id = u64;
+}
+
+interface api {
+	use types.{point};
+	get: func() -> point;
+	set: func(p: point);
+	reset: func();
+}
+
+interface legacy {
+	ping: func();
+}
+
+world w {
+	import api;
+	import log: func(msg: string);
+	export run: func();
+}
+`)
+	new := parse(`package foo:bar@2.0.0;
+
+interface types {
+	/// A point in 2D space.
+	record point { x: u32, y: u32 }
+	enum color { red, green, blue }
+	type id = u64;
+	type name = string;
+}
+
+interface api {
+	use types.{point};
+	get: func() -> point;
+	set: func(p: point, notify: bool);
+	clear: func();
+}
+
+interface extra {
+	pong: func();
+}
+
+world w {
+	import api;
+	import log: func(msg: string);
+	export run: func();
+	export stop: func();
+}
+`)
+	want := []string{
+		"added function foo:bar/w export stop (breaking)",
+		"modified type foo:bar/types#color (breaking)",
+		"added type foo:bar/types#name",
+		"modified function foo:bar/api#set (breaking)",
+		"removed function foo:bar/api#reset (breaking)",
+		"added function foo:bar/api#clear",
+		"removed interface foo:bar/legacy (breaking)",
+		"added interface foo:bar/extra",
+	}
+	var got []string
+	for _, c := range Diff(old, new) {
+		got = append(got, c.String())
+	}
+	if g, w := strings.Join(got, "\n"), strings.Join(want, "\n"); g != w {
+		t.Errorf("Diff():\n%s\n\nexpected:\n%s", g, w)
+	}
+
+	if changes := Diff(old, old); len(changes) != 0 {
+		t.Errorf("Diff(old, old): %d changes, expected none", len(changes))
+	}
+}
@@ -42,7 +42,253 @@
 // [Component]: https://component-model.bytecodealliance.org/introduction.html
 package wit
 
+import "strings"
+
 // Docs represent WIT documentation text extracted from comments.
 type Docs struct {
 	Contents string // may be empty
 }
+
+// Markdown returns the contents of [Docs] d as [CommonMark] text, suitable for generating documentation.
+// Trailing whitespace is removed, and runs of blank lines are collapsed into a single paragraph break.
+// Other Markdown syntax, such as emphasis and links, is preserved.
+//
+// Outside of code spans and fenced code blocks, the HTML metacharacters "<", ">", and "&" are escaped,
+// so WIT types like list<u8> are not interpreted as HTML. Each reference to a WIT identifier,
+// such as [fields], is passed to link without a leading "%" (see [Docs.References]).
+// If link returns true, the reference is linked to the returned URL, e.g. [fields](#fields).
+// Otherwise, or if link is nil, its brackets are escaped so it is rendered as text.
+//
+// [CommonMark]: https://commonmark.org/
+func (d *Docs) Markdown(link func(ref string) (url string, ok bool)) string {
+	var b strings.Builder
+	var fence string
+	var blank bool
+	for _, line := range strings.Split(d.Contents, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		trimmed := strings.TrimLeft(line, " ")
+		if fence != "" {
+			b.WriteString("\n")
+			b.WriteString(line)
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if line == "" {
+			blank = b.Len() > 0
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		if blank {
+			b.WriteString("\n")
+			blank = false
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			b.WriteString(line)
+			continue
+		}
+		writeMarkdownLine(&b, line, link)
+	}
+	return b.String()
+}
+
+// writeMarkdownLine writes a single line of Markdown text to b,
+// escaping HTML and linking references to WIT identifiers.
+func writeMarkdownLine(b *strings.Builder, line string, link func(string) (string, bool)) {
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; c {
+		case '\\':
+			// Preserve escape sequences
+			b.WriteByte(c)
+			if i+1 < len(line) {
+				i++
+				b.WriteByte(line[i])
+			}
+		case '`':
+			// Copy code spans verbatim
+			n := len(line[i:]) - len(strings.TrimLeft(line[i:], "`"))
+			ticks := line[i : i+n]
+			end := strings.Index(line[i+n:], ticks)
+			if end < 0 {
+				b.WriteString(ticks)
+				i += n - 1
+				continue
+			}
+			end += i + n + n
+			b.WriteString(line[i:end])
+			i = end - 1
+		case '<', '>', '&':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '[':
+			name, _, found := strings.Cut(line[i+1:], "]")
+			next := i + 1 + len(name) + 1
+			if !found || !isDocsRef(name) || (i > 0 && line[i-1] == ']') || (next < len(line) && strings.IndexByte("([:", line[next]) >= 0) {
+				b.WriteByte(c)
+				continue
+			}
+			if url, ok := resolveDocsRef(name, link); ok {
+				b.WriteString("[" + name + "](" + url + ")")
+			} else {
+				b.WriteString("\\[" + name + "\\]")
+			}
+			i = next - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+}
+
+func resolveDocsRef(name string, link func(string) (string, bool)) (string, bool) {
+	if link == nil {
+		return "", false
+	}
+	return link(strings.TrimPrefix(name, "%"))
+}
+
+// References returns the WIT identifiers referred to by [Docs] d, in order of first appearance,
+// without duplicates. A reference is an identifier in square brackets, such as [fields] or
+// [fields.get], linked by [Docs.Markdown], or a code span containing only an identifier,
+// such as `+"`input-stream`"+`. A leading "%" is removed. References in fenced code blocks are ignored.
+//
+// References are not resolved; callers may match them against the names of known types,
+// functions, and interfaces, e.g. with [ParseTypeRef].
+func (d *Docs) References() []string {
+	var refs []string
+	seen := make(map[string]bool)
+	add := func(s string) {
+		if !isDocsRef(s) {
+			return
+		}
+		s = strings.TrimPrefix(s, "%")
+		if !seen[s] {
+			seen[s] = true
+			refs = append(refs, s)
+		}
+	}
+	var fence string
+	for _, line := range strings.Split(d.Contents, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+		for i := 0; i < len(line); i++ {
+			switch line[i] {
+			case '\\':
+				i++
+			case '`':
+				n := len(line[i:]) - len(strings.TrimLeft(line[i:], "`"))
+				end := strings.Index(line[i+n:], line[i:i+n])
+				if end < 0 {
+					i += n - 1
+					continue
+				}
+				add(strings.TrimSpace(line[i+n : i+n+end]))
+				i += n + end + n - 1
+			case '[':
+				name, _, found := strings.Cut(line[i+1:], "]")
+				next := i + 1 + len(name) + 1
+				if found && (i == 0 || line[i-1] != ']') && (next >= len(line) || strings.IndexByte("([:", line[next]) < 0) {
+					add(name)
+					i = next - 1
+				}
+			}
+		}
+	}
+	return refs
+}
+
+// isDocsRef reports whether s is a reference to a WIT identifier, such as "fields",
+// "%list", or "fields.get".
+func isDocsRef(s string) bool {
+	s = strings.TrimPrefix(s, "%")
+	if s == "" || !(s[0] >= 'a' && s[0] <= 'z' || s[0] >= 'A' && s[0] <= 'Z') {
+		return false
+	}
+	for _, c := range s {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+// GoComment returns the contents of [Docs] d formatted as Go [doc comments], with each line
+// prefixed by indent and "//". Long lines are wrapped at [LineLength], and Markdown code fences
+// are converted to indented code blocks. Control characters such as carriage returns are removed,
+// so the result is always valid Go source.
+// Markdown link definitions are never wrapped. It returns an empty string if d has no contents.
+//
+// [doc comments]: https://go.dev/doc/comment
+func (d *Docs) GoComment(indent string) string {
+	if d.Contents == "" {
+		return ""
+	}
+	var lines []string
+	var code bool
+	for _, line := range strings.Split(sanitizeGoComment(d.Contents), "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			code = !code
+			lines = append(lines, "")
+		case code && trimmed != "":
+			lines = append(lines, "\t"+strings.TrimRight(line, " \t"))
+		case isLinkDef(trimmed):
+			// Link definitions cannot span lines
+			lines = append(lines, " "+trimmed)
+		default:
+			var b strings.Builder
+			for _, c := range strings.TrimRight(line, " \t") {
+				switch {
+				case c == ' ' && b.Len() == 0:
+					// Ignore leading spaces
+					continue
+				case c == ' ' && len("//")+b.Len() > LineLength:
+					lines = append(lines, b.String())
+					b.Reset()
+					continue
+				case b.Len() == 0:
+					b.WriteRune(' ')
+				}
+				b.WriteRune(c)
+			}
+			lines = append(lines, b.String())
+		}
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(indent + "//" + line + "\n")
+	}
+	return b.String()
+}
+
+// isLinkDef reports whether line is a Markdown link reference definition, e.g. "[name]: https://example.com".
+func isLinkDef(line string) bool {
+	name, rest, found := strings.Cut(line, "]: ")
+	return found && len(name) > 1 && name[0] == '[' && !strings.ContainsAny(name[1:], "[]") && rest != ""
+}
+
+// sanitizeGoComment removes control characters other than newlines and tabs from s.
+func sanitizeGoComment(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r != '\n' && r != '\t' && (r < 0x20 || r == 0x7f) {
+			return -1
+		}
+		return r
+	}, s)
+}
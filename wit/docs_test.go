@@ -0,0 +1,117 @@
+package wit
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDocsMarkdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		want     string
+	}{
+		{"empty", "", ""},
+		{"plain", "A plain sentence.", "A plain sentence."},
+		{"trailing whitespace", "Line one.  \nLine two.\t\n", "Line one.\nLine two."},
+		{"paragraphs", "\nOne.\n\n\n\nTwo.\n\n", "One.\n\nTwo."},
+		{"html", "Returns a list<u8> & more, or <b>&amp;</b>.", "Returns a list\\<u8\\> \\& more, or \\<b\\>\\&amp;\\</b\\>."},
+		{"code span", "Returns `list<u8>` or ``a ` b<c>``.", "Returns `list<u8>` or ``a ` b<c>``."},
+		{"unterminated code span", "A `list<u8>", "A `list\\<u8\\>"},
+		{"escape", "A \\[fields] \\<b>", "A \\[fields] \\<b\\>"},
+		{"reference", "See [fields] and [%list].", "See [fields](#fields) and [%list](#list)."},
+		{"method reference", "Calls [fields.get].", "Calls [fields.get](#fields.get)."},
+		{"unresolved reference", "See [missing] or [%missing].", "See \\[missing\\] or \\[%missing\\]."},
+		{"markdown", "Some *emphasis* and _more_.\n\n- item", "Some *emphasis* and _more_.\n\n- item"},
+		{"link", "A [link](https://example.com) or [ref][x].", "A [link](https://example.com) or [ref][x]."},
+		{"not a reference", "Items [1] and [a b].", "Items [1] and [a b]."},
+		{"code fence", "Example:\n\n```wit\nf: func() -> list<u8>;\n\n[fields]\n```\nDone.", "Example:\n\n```wit\nf: func() -> list<u8>;\n\n[fields]\n```\nDone."},
+	}
+	link := func(ref string) (string, bool) {
+		switch ref {
+		case "fields", "list", "fields.get":
+			return "#" + ref, true
+		}
+		return "", false
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Docs{Contents: tt.contents}
+			got := d.Markdown(link)
+			if got != tt.want {
+				t.Errorf("Markdown(): got %q, expected %q", got, tt.want)
+			}
+		})
+	}
+
+	d := Docs{Contents: "See [fields]."}
+	if got, want := d.Markdown(nil), "See \\[fields\\]."; got != want {
+		t.Errorf("Markdown(nil): got %q, expected %q", got, want)
+	}
+}
+
+func TestDocsReferences(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		want     []string
+	}{
+		{"empty", "", nil},
+		{"brackets", "See [fields], [%list], and [fields.get].", []string{"fields", "list", "fields.get"}},
+		{"code spans", "Returns an `input-stream` or ``error``.", []string{"input-stream", "error"}},
+		{"duplicates", "A [fields] is `fields`.", []string{"fields"}},
+		{"not references", "A `list<u8>`, [1], [a b], [link](https://example.com), [ref][x], and \\[escaped].", nil},
+		{"code fence", "```\n[fields]\n```\nSee `pollable`.", []string{"pollable"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Docs{Contents: tt.contents}
+			if got := d.References(); !slices.Equal(got, tt.want) {
+				t.Errorf("References(): %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDocsGoComment(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		indent   string
+		want     string
+	}{
+		{"empty", "", "", ""},
+		{"plain", "A plain sentence.", "", "// A plain sentence.\n"},
+		{"indent", "One.\n\nTwo.", "\t", "\t// One.\n\t//\n\t// Two.\n"},
+		{"carriage return", "One.\r\nTwo.\r\n", "", "// One.\n// Two.\n"},
+		{"control characters", "A\x00B\x1bC\x7f", "", "// ABC\n"},
+		{"block comment", "Not /* a */ comment.", "", "// Not /* a */ comment.\n"},
+		{
+			"wrap",
+			"Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor incididunt ut labore et dolore magna aliqua.",
+			"",
+			"// Lorem ipsum dolor sit amet, consectetur adipiscing elit, sed do eiusmod tempor\n// incididunt ut labore et dolore magna aliqua.\n",
+		},
+		{
+			"link definition",
+			"See [Unix Time].\n\n[Unix Time]: https://en.wikipedia.org/wiki/Unix_time#a-very-long-fragment-that-pushes-this-line-past-the-wrapping-limit",
+			"",
+			"// See [Unix Time].\n//\n// [Unix Time]: https://en.wikipedia.org/wiki/Unix_time#a-very-long-fragment-that-pushes-this-line-past-the-wrapping-limit\n",
+		},
+		{
+			"code fence",
+			"Example:\n```wit\nf: func() -> list<u8>; /* comment */\n\n  g: func();\n```\nDone.\n```\ncode\n```",
+			"",
+			"// Example:\n//\n//\tf: func() -> list<u8>; /* comment */\n//\n//\t  g: func();\n//\n// Done.\n//\n//\tcode\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Docs{Contents: tt.contents}
+			got := d.GoComment(tt.indent)
+			if got != tt.want {
+				t.Errorf("GoComment(%q): got %q, expected %q", tt.indent, got, tt.want)
+			}
+		})
+	}
+}
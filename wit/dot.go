@@ -0,0 +1,83 @@
+package wit
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DOT writes a [Graphviz DOT] graph of the packages, worlds, and interfaces in [Resolve] r to w.
+// Each package is drawn as a cluster containing its worlds and interfaces, labeled with their
+// qualified names and colored by kind. Edges connect worlds to the interfaces they import
+// or export, and interfaces to the interfaces they use types from.
+//
+// [Graphviz DOT]: https://graphviz.org/doc/info/lang.html
+func (r *Resolve) DOT(w io.Writer) error {
+	ids := make(map[Node]string)
+	labels := make(map[*Interface]string)
+	for i, face := range r.Interfaces {
+		ids[face] = "i" + strconv.Itoa(i)
+		labels[face] = interfaceName(face)
+	}
+	for i, world := range r.Worlds {
+		ids[world] = "w" + strconv.Itoa(i)
+		// Anonymous interfaces are labeled with the world and the name they are imported or exported as
+		world.AllItems()(func(name string, item WorldItem) bool {
+			if ref, ok := item.(*InterfaceRef); ok && ref.Interface.Name == nil {
+				labels[ref.Interface] = worldName(world) + "." + name
+			}
+			return true
+		})
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph wit {\n")
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [shape=box, style=filled];\n")
+
+	for i, p := range r.Packages {
+		fmt.Fprintf(&b, "\tsubgraph cluster_p%d {\n", i)
+		fmt.Fprintf(&b, "\t\tlabel=%s;\n", strconv.Quote(p.Name.String()))
+		b.WriteString("\t\tstyle=filled;\n\t\tcolor=gray95;\n")
+		for _, face := range r.Interfaces {
+			if face.Package == p {
+				fmt.Fprintf(&b, "\t\t%s [label=%s, fillcolor=lightblue];\n", ids[face], strconv.Quote(labels[face]))
+			}
+		}
+		for _, world := range r.Worlds {
+			if world.Package == p {
+				id := world.Package.Name
+				id.Extension = world.Name
+				fmt.Fprintf(&b, "\t\t%s [label=%s, fillcolor=lightgoldenrod];\n", ids[world], strconv.Quote(id.String()))
+			}
+		}
+		b.WriteString("\t}\n")
+	}
+
+	for _, face := range r.Interfaces {
+		for _, dep := range face.uses() {
+			if id, ok := ids[dep]; ok {
+				fmt.Fprintf(&b, "\t%s -> %s [label=\"use\", style=dashed];\n", ids[face], id)
+			}
+		}
+	}
+	for _, world := range r.Worlds {
+		edges := func(direction string) func(string, WorldItem) bool {
+			return func(_ string, item WorldItem) bool {
+				if ref, ok := item.(*InterfaceRef); ok {
+					if id, ok := ids[ref.Interface]; ok {
+						fmt.Fprintf(&b, "\t%s -> %s [label=%q];\n", ids[world], id, direction)
+					}
+				}
+				return true
+			}
+		}
+		world.Imports.All()(edges("import"))
+		world.Exports.All()(edges("export"))
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
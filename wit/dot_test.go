@@ -0,0 +1,55 @@
+package wit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDOT(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:bar@1.0.0;
+
+interface types {
+	type t = u32;
+}
+
+interface api {
+	use types.{t};
+	get: func() -> t;
+}
+
+world w {
+	import api;
+	export run: interface {
+		run: func();
+	}
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := res.DOT(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := `digraph wit {
+	rankdir=LR;
+	node [shape=box, style=filled];
+	subgraph cluster_p0 {
+		label="foo:bar@1.0.0";
+		style=filled;
+		color=gray95;
+		i0 [label="foo:bar/types@1.0.0", fillcolor=lightblue];
+		i1 [label="foo:bar/api@1.0.0", fillcolor=lightblue];
+		i2 [label="foo:bar/w.run", fillcolor=lightblue];
+		w0 [label="foo:bar/w@1.0.0", fillcolor=lightgoldenrod];
+	}
+	i1 -> i0 [label="use", style=dashed];
+	w0 -> i0 [label="import"];
+	w0 -> i1 [label="import"];
+	w0 -> i2 [label="export"];
+}
+`
+	if got := b.String(); got != want {
+		t.Errorf("DOT():\n%s\nexpected:\n%s", got, want)
+	}
+}
@@ -0,0 +1,26 @@
+package wit
+
+// ErrorContext represents the WIT [error-context type], part of the Component Model async proposal
+// expected in [WASI Preview 3]. An error-context value is an opaque handle to debugging
+// information about an error. It implements the [Node], [ABI], and [TypeDefKind] interfaces.
+//
+// [error-context type]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/Explainer.md#error-context-type
+// [WASI Preview 3]: https://bytecodealliance.org/articles/webassembly-the-updated-roadmap-for-developers
+type ErrorContext struct {
+	_typeDefKind
+}
+
+// Size returns the [ABI byte size] for an [ErrorContext], represented as a 32-bit handle.
+//
+// [ABI byte size]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#size
+func (*ErrorContext) Size() uintptr { return 4 }
+
+// Align returns the [ABI byte alignment] for an [ErrorContext].
+//
+// [ABI byte alignment]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#alignment
+func (*ErrorContext) Align() uintptr { return 4 }
+
+// Flat returns the [flattened] ABI representation of [ErrorContext].
+//
+// [flattened]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#flattening
+func (*ErrorContext) Flat() []Type { return []Type{U32{}} }
@@ -0,0 +1,93 @@
+package wit
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestErrorContextParseWIT(t *testing.T) {
+	const src = `package foo:bar;
+
+interface i {
+	type e = error-context;
+	f: func(x: error-context) -> result<u32, error-context>;
+}
+`
+	res, err := ParseWIT(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := res.WIT(nil, ""); got != src {
+		t.Errorf("WIT():\n%s\nexpected:\n%s", got, src)
+	}
+	e := res.Interfaces[0].TypeDefs.Get("e")
+	if _, ok := e.Kind.(*ErrorContext); !ok {
+		t.Errorf("e.Kind: %T, expected *ErrorContext", e.Kind)
+	}
+	if e.Size() != 4 || e.Align() != 4 || len(e.Flat()) != 1 {
+		t.Errorf("e: size %d, align %d, flat %v, expected 4, 4, [u32]", e.Size(), e.Align(), e.Flat())
+	}
+	if _, err := ParseWIT(strings.NewReader("package foo:bar;\ninterface i { type e = error-context<u8>; }\n")); err == nil {
+		t.Error("ParseWIT(error-context<u8>): nil error, expected error")
+	}
+}
+
+func TestErrorContextDecodeJSON(t *testing.T) {
+	// Older versions of wasm-tools emit error-context as a type kind,
+	// and newer versions emit it as a primitive type.
+	const data = `{
+	"worlds": [],
+	"interfaces": [
+		{
+			"name": "i",
+			"types": {"e": 0},
+			"functions": {
+				"f": {
+					"name": "f",
+					"kind": "freestanding",
+					"params": [{"name": "x", "type": "error-context"}],
+					"results": [{"type": 0}]
+				}
+			},
+			"package": 0
+		}
+	],
+	"types": [
+		{
+			"name": "e",
+			"kind": "error-context",
+			"owner": {"interface": 0}
+		}
+	],
+	"packages": [
+		{
+			"name": "foo:bar",
+			"interfaces": {"i": 0}
+		}
+	]
+}`
+	res, err := DecodeJSON(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `package foo:bar;
+
+interface i {
+	type e = error-context;
+	f: func(x: error-context) -> e;
+}
+`
+	if got := res.WIT(nil, ""); got != want {
+		t.Errorf("WIT():\n%s\nexpected:\n%s", got, want)
+	}
+
+	f := res.Interfaces[0].Functions.Get("f")
+	x, ok := f.Params[0].Type.(*TypeDef)
+	if !ok {
+		t.Fatalf("f.Params[0].Type: %T, expected *TypeDef", f.Params[0].Type)
+	}
+	if !slices.Contains(res.TypeDefs, x) {
+		t.Errorf("f.Params[0].Type not found in res.TypeDefs")
+	}
+}
@@ -0,0 +1,34 @@
+package wit
+
+import "errors"
+
+// Errors returned by functions in this package. Returned errors may wrap these with
+// additional context, so callers should test for them with [errors.Is].
+var (
+	// ErrWasmToolsNotFound is returned when the wasm-tools executable is not found in $PATH.
+	ErrWasmToolsNotFound = errors.New("wasm-tools not found")
+
+	// ErrWasmToolsTimeout is returned by a [Loader] when wasm-tools runs longer than [Loader.Timeout].
+	ErrWasmToolsTimeout = errors.New("wasm-tools timed out")
+
+	// ErrWasmToolsWarnings is returned by a [Loader] with [Loader.StrictWarnings] set
+	// when wasm-tools succeeds with warnings.
+	ErrWasmToolsWarnings = errors.New("wasm-tools reported warnings")
+
+	// ErrInvalidPackageName is returned by [ParseIdent] and [Ident.Validate] for a malformed identifier.
+	ErrInvalidPackageName = errors.New("invalid package name")
+
+	// ErrPackageNotFound is returned by [Resolve.ResolvePackageRef] if no package matches.
+	ErrPackageNotFound = errors.New("package not found")
+
+	// ErrAmbiguousPackage is returned by [Resolve.ResolvePackageRef] if more than one
+	// version of a package matches a reference without a version.
+	ErrAmbiguousPackage = errors.New("ambiguous package")
+
+	// ErrUnknownType is returned by [ParseType] for an unrecognized primitive type.
+	ErrUnknownType = errors.New("unknown type")
+
+	// ErrDecodeFailed is returned by [DecodeJSON] and the functions that call it
+	// when JSON cannot be decoded. It wraps the underlying error.
+	ErrDecodeFailed = errors.New("decode failed")
+)
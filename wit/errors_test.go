@@ -0,0 +1,45 @@
+package wit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		f    func() error
+		want error
+	}{
+		{"ParseIdent missing namespace", func() error { _, err := ParseIdent("foo"); return err }, ErrInvalidPackageName},
+		{"ParseIdent missing package", func() error { _, err := ParseIdent("foo:"); return err }, ErrInvalidPackageName},
+		{"ParseIdent bad version", func() error { _, err := ParseIdent("foo:bar@x"); return err }, ErrInvalidPackageName},
+		{"ParseType", func() error { _, err := ParseType("u128"); return err }, ErrUnknownType},
+		{"DecodeJSON syntax", func() error { _, err := DecodeJSON(strings.NewReader("{")); return err }, ErrDecodeFailed},
+		{"DecodeJSON unknown type", func() error {
+			_, err := DecodeJSON(strings.NewReader(`{"types": [{"kind": {"type": "u128"}}]}`))
+			return err
+		}, ErrDecodeFailed},
+		{"DecodeJSON wraps underlying error", func() error {
+			_, err := DecodeJSON(strings.NewReader(`{"types": [{"kind": {"type": "u128"}}]}`))
+			return err
+		}, ErrUnknownType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.f()
+			if !errors.Is(err, tt.want) {
+				t.Errorf("%v, expected error wrapping %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWasmToolsNotFound(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	_, err := DecodeWIT(strings.NewReader("package foo:bar;"))
+	if !errors.Is(err, ErrWasmToolsNotFound) {
+		t.Errorf("DecodeWIT: %v, expected error wrapping %v", err, ErrWasmToolsNotFound)
+	}
+}
@@ -0,0 +1,178 @@
+package wit
+
+import (
+	"slices"
+
+	"go.bytecodealliance.org/wit/ordered"
+)
+
+// PackageFilter selects packages in a [Resolve] by name. See [Resolve.FilterPackages].
+//
+// A name without a version matches every version of a package, and a name with a version
+// matches only that version. The Extension of each name is ignored.
+type PackageFilter struct {
+	// Include lists the packages to keep. If empty, all packages are kept
+	// unless they are listed in Exclude.
+	Include []Ident
+
+	// Exclude lists the packages to remove. Exclude takes precedence over Include.
+	Exclude []Ident
+}
+
+// FilterPackages removes the packages from [Resolve] r not selected by [PackageFilter] f,
+// along with their worlds, interfaces, and any types used only by removed items.
+//
+// A package that a selected package depends on is always kept, even if it is excluded,
+// so r remains consistent: each remaining world, interface, and type refers only to
+// items that are also in r. This is applied after decoding, e.g. with [DecodeJSON],
+// to reduce the size of a Resolve before further processing or code generation.
+func (r *Resolve) FilterPackages(f PackageFilter) {
+	keep := make(map[*Package]bool)
+	for _, p := range r.Packages {
+		if (len(f.Include) == 0 || matchesAnyPackage(p, f.Include)) && !matchesAnyPackage(p, f.Exclude) {
+			keep[p] = true
+		}
+	}
+
+	// Keep dependencies of kept packages
+	for changed := true; changed; {
+		changed = false
+		for _, p := range r.Packages {
+			if keep[p] {
+				continue
+			}
+			for _, kept := range r.Packages {
+				if keep[kept] && DependsOn(kept, p) {
+					keep[p] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	r.Packages = slices.DeleteFunc(r.Packages, func(p *Package) bool { return !keep[p] })
+	r.Worlds = slices.DeleteFunc(r.Worlds, func(w *World) bool { return !keep[w.Package] })
+	r.Interfaces = slices.DeleteFunc(r.Interfaces, func(i *Interface) bool { return !keep[i.Package] })
+
+	// Keep types reachable from the remaining worlds and interfaces
+	n := &normalizer{
+		interfaces: make(map[*Interface]bool),
+		types:      make(map[*TypeDef]bool),
+	}
+	for _, i := range r.Interfaces {
+		i.TypeDefs.All()(func(_ string, t *TypeDef) bool {
+			n.visitType(t)
+			return true
+		})
+		i.Functions.All()(func(_ string, f *Function) bool {
+			n.visitFunction(f)
+			return true
+		})
+	}
+	for _, w := range r.Worlds {
+		w.AllItems()(func(_ string, item WorldItem) bool {
+			switch item := item.(type) {
+			case *TypeDef:
+				n.visitType(item)
+			case *Function:
+				n.visitFunction(item)
+			}
+			return true
+		})
+	}
+	r.TypeDefs = slices.DeleteFunc(r.TypeDefs, func(t *TypeDef) bool { return !n.types[t] })
+}
+
+// matchesAnyPackage returns true if the name of [Package] p matches any of names.
+func matchesAnyPackage(p *Package, names []Ident) bool {
+	for _, name := range names {
+		if p.Name.Namespace != name.Namespace || p.Name.Package != name.Package {
+			continue
+		}
+		if name.Version == nil || (p.Name.Version != nil && p.Name.Version.Equal(*name.Version)) {
+			return true
+		}
+	}
+	return false
+}
+
+// PublicSubset removes the items in [Resolve] r that are not reachable from the imports and
+// exports of any [World] in r, and returns r. Reachable items are the interfaces imported or
+// exported by a world, the functions of those interfaces and of each world, the types those
+// functions and the types imported by each world refer to, and the interfaces that own them.
+// Types that are declared but never used by a reachable function or type, such as helper
+// types, and interfaces not used by any world, are removed, along with any packages left empty.
+//
+// PublicSubset modifies r in place, like [Resolve.FilterPackages], so r remains consistent:
+// the types of each interface and the interfaces of each package are updated to match.
+func (r *Resolve) PublicSubset() *Resolve {
+	n := &normalizer{
+		interfaces: make(map[*Interface]bool),
+		types:      make(map[*TypeDef]bool),
+	}
+	var queue []*Interface
+	keep := func(i *Interface) {
+		if !n.interfaces[i] {
+			n.interfaces[i] = true
+			queue = append(queue, i)
+		}
+	}
+	for _, w := range r.Worlds {
+		w.AllItems()(func(_ string, item WorldItem) bool {
+			switch item := item.(type) {
+			case *InterfaceRef:
+				keep(item.Interface)
+			case *TypeDef:
+				n.visitType(item)
+			case *Function:
+				n.visitFunction(item)
+			}
+			return true
+		})
+	}
+	for seen := 0; ; {
+		for ; len(queue) > 0; queue = queue[1:] {
+			queue[0].Functions.All()(func(_ string, f *Function) bool {
+				n.visitFunction(f)
+				return true
+			})
+		}
+		if seen == len(n.sortedTypes) {
+			break
+		}
+		for _, t := range n.sortedTypes[seen:] {
+			if i, ok := t.Owner.(*Interface); ok {
+				keep(i)
+			}
+		}
+		seen = len(n.sortedTypes)
+	}
+
+	r.Interfaces = slices.DeleteFunc(r.Interfaces, func(i *Interface) bool { return !n.interfaces[i] })
+	r.TypeDefs = slices.DeleteFunc(r.TypeDefs, func(t *TypeDef) bool { return !n.types[t] })
+	for _, i := range r.Interfaces {
+		var types ordered.Map[string, *TypeDef]
+		i.TypeDefs.All()(func(name string, t *TypeDef) bool {
+			if n.types[t] {
+				types.Set(name, t)
+			}
+			return true
+		})
+		i.TypeDefs = types
+	}
+	for _, p := range r.Packages {
+		var interfaces ordered.Map[string, *Interface]
+		p.Interfaces.All()(func(name string, i *Interface) bool {
+			if n.interfaces[i] {
+				interfaces.Set(name, i)
+			}
+			return true
+		})
+		p.Interfaces = interfaces
+	}
+	r.Packages = slices.DeleteFunc(r.Packages, func(p *Package) bool {
+		return p.Interfaces.Len() == 0 && p.Worlds.Len() == 0
+	})
+	return r
+}
@@ -0,0 +1,201 @@
+package wit
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+const filterWIT = `package app:main;
+
+world w {
+	import io:streams/streams@1.0.0;
+}
+
+package io:streams@1.0.0 {
+	interface streams {
+		resource input-stream {
+			read: func(n: u64) -> list<u8>;
+		}
+	}
+}
+
+package io:streams@2.0.0 {
+	interface streams {
+		type bytes = list<u8>;
+	}
+}
+
+package other:pkg {
+	interface api {
+		record r { a: option<string> }
+		get: func() -> tuple<r, u32>;
+	}
+}
+`
+
+func TestResolveFilterPackages(t *testing.T) {
+	ident := func(s string) Ident {
+		id, err := ParseIdent(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+	tests := []struct {
+		name   string
+		filter PackageFilter
+		want   []string
+	}{
+		{"none", PackageFilter{}, []string{"io:streams@1.0.0", "io:streams@2.0.0", "other:pkg", "app:main"}},
+		{"include", PackageFilter{Include: []Ident{ident("other:pkg")}}, []string{"other:pkg"}},
+		{"include all versions", PackageFilter{Include: []Ident{ident("io:streams")}}, []string{"io:streams@1.0.0", "io:streams@2.0.0"}},
+		{"include version", PackageFilter{Include: []Ident{ident("io:streams@2.0.0")}}, []string{"io:streams@2.0.0"}},
+		{"exclude", PackageFilter{Exclude: []Ident{ident("other:pkg")}}, []string{"io:streams@1.0.0", "io:streams@2.0.0", "app:main"}},
+		{"include dependency", PackageFilter{Include: []Ident{ident("app:main")}}, []string{"io:streams@1.0.0", "app:main"}},
+		{"exclude dependency", PackageFilter{Include: []Ident{ident("app:main")}, Exclude: []Ident{ident("io:streams")}}, []string{"io:streams@1.0.0", "app:main"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := ParseWIT(strings.NewReader(filterWIT))
+			if err != nil {
+				t.Fatal(err)
+			}
+			res.FilterPackages(tt.filter)
+			var got []string
+			for _, p := range res.Packages {
+				got = append(got, p.Name.String())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("packages: %v, expected %v", got, tt.want)
+			}
+			for _, w := range res.Worlds {
+				if !slices.Contains(res.Packages, w.Package) {
+					t.Errorf("world %s remains after its package was removed", w.Name)
+				}
+			}
+			for _, i := range res.Interfaces {
+				if !slices.Contains(res.Packages, i.Package) {
+					t.Errorf("interface %s remains after its package was removed", interfaceName(i))
+				}
+			}
+			for _, td := range res.TypeDefs {
+				if owner, ok := td.Owner.(*Interface); ok && !slices.Contains(res.Interfaces, owner) {
+					t.Errorf("type %s remains after its owner was removed", td.TypeName())
+				}
+				for _, dep := range kindTypes(td.Kind) {
+					if dep, ok := dep.(*TypeDef); ok && !slices.Contains(res.TypeDefs, dep) {
+						t.Errorf("type %s refers to removed type %s", td.TypeName(), dep.TypeName())
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestResolveFilterPackagesTypes(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(filterWIT))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := ParseIdent("other:pkg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.FilterPackages(PackageFilter{Include: []Ident{id}})
+	var got []string
+	for _, td := range res.TypeDefs {
+		got = append(got, td.WITKind())
+	}
+	want := []string{"option", "record", "tuple"}
+	if !slices.Equal(got, want) {
+		t.Errorf("types: %v, expected %v", got, want)
+	}
+}
+
+func TestResolvePublicSubset(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package app:main;
+
+interface types {
+	record used { x: u32 }
+	record unused { y: list<u8> }
+	type unused-alias = used;
+}
+
+interface handles {
+	resource file {
+		read: func() -> list<u8>;
+	}
+}
+
+interface api {
+	use types.{used};
+	use handles.{file};
+	record helper { z: string }
+	get: func() -> used;
+	open: func() -> file;
+}
+
+interface orphan {
+	record o { a: u32 }
+	f: func(o: o);
+}
+
+world w {
+	import api;
+	export run: func(s: list<string>);
+}
+
+package other:unused {
+	interface i {
+		g: func();
+	}
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := res.PublicSubset(); got != res {
+		t.Errorf("PublicSubset: did not return r")
+	}
+	if err := res.Validate(); err != nil {
+		t.Error(err)
+	}
+	want := `package app:main;
+
+interface types {
+	record used { x: u32 }
+}
+
+interface handles {
+	resource file {
+		read: func() -> list<u8>;
+	}
+}
+
+interface api {
+	use types.{used};
+	use handles.{file};
+	get: func() -> used;
+	open: func() -> file;
+}
+
+world w {
+	import types;
+	import handles;
+	import api;
+	export run: func(s: list<string>);
+}
+`
+	if got := res.WIT(nil, ""); got != want {
+		t.Errorf("WIT after PublicSubset:\n%s\nexpected:\n%s", got, want)
+	}
+	if len(res.Packages) != 1 || len(res.Interfaces) != 3 {
+		t.Errorf("PublicSubset: %d packages and %d interfaces, expected 1 and 3", len(res.Packages), len(res.Interfaces))
+	}
+	for _, td := range res.TypeDefs {
+		if td.Name != nil && (*td.Name == "unused" || *td.Name == "helper" || *td.Name == "o") {
+			t.Errorf("PublicSubset: type %s not removed", *td.Name)
+		}
+	}
+}
@@ -3,6 +3,11 @@ package wit
 // Flags represents a WIT [flags type], stored as a bitfield.
 // It implements the [Node], [ABI], and [TypeDefKind] interfaces.
 //
+// The Canonical ABI represents more than 32 flags as multiple u32 values,
+// one per 32 flags, which is reflected in [Flags.Size], [Flags.Flat], and [Flags.Repr].
+// Newer versions of the Component Model limit a flags type to 32 flags,
+// but the multi-word representation is retained for compatibility with older WIT.
+//
 // [flags type]: https://component-model.bytecodealliance.org/design/wit.html#flags
 type Flags struct {
 	_typeDefKind
@@ -48,6 +53,37 @@ func (f *Flags) Flat() []Type {
 	return flat
 }
 
+// Repr returns the unsigned integer [Type] that represents [Flags] f in the [Canonical ABI]:
+// [U8] for up to 8 flags, [U16] for up to 16 flags, or [U32] for up to 32 flags.
+// More than 32 flags are represented as a sequence of u32 values, returned as an
+// anonymous [TypeDef] with a [Tuple] of [U32].
+//
+// [Canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#flags
+func (f *Flags) Repr() Type {
+	n := len(f.Flags)
+	switch {
+	case n <= 8:
+		return U8{}
+	case n <= 16:
+		return U16{}
+	case n <= 32:
+		return U32{}
+	}
+	return &TypeDef{Kind: &Tuple{Types: f.Flat()}}
+}
+
+// BitOf returns the bit position of the flag named name in [Flags] f, or -1 if not found.
+// Flags are numbered from 0 in declaration order. For more than 32 flags, bit n is
+// bit n%32 of the u32 value at index n/32 in [Flags.Repr].
+func (f *Flags) BitOf(name string) int {
+	for i := range f.Flags {
+		if f.Flags[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
 // Flag represents a single flag value in a [Flags] type.
 // It implements the [Node] interface.
 type Flag struct {
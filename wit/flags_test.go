@@ -0,0 +1,98 @@
+package wit
+
+import (
+	"strconv"
+	"testing"
+)
+
+func makeFlags(n int) *Flags {
+	f := &Flags{}
+	for i := 0; i < n; i++ {
+		f.Flags = append(f.Flags, Flag{Name: "f" + strconv.Itoa(i)})
+	}
+	return f
+}
+
+func TestFlagsRepr(t *testing.T) {
+	tests := []struct {
+		n     int
+		want  Type
+		words int
+	}{
+		{0, U8{}, 0},
+		{1, U8{}, 0},
+		{8, U8{}, 0},
+		{9, U16{}, 0},
+		{16, U16{}, 0},
+		{17, U32{}, 0},
+		{32, U32{}, 0},
+		{33, nil, 2},
+		{64, nil, 2},
+		{65, nil, 3},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.n), func(t *testing.T) {
+			f := makeFlags(tt.n)
+			got := f.Repr()
+			if tt.want != nil {
+				if got != tt.want {
+					t.Errorf("Repr(): %v, expected %v", got, tt.want)
+				}
+			} else {
+				tuple := KindOf[*Tuple](got)
+				if tuple == nil {
+					t.Fatalf("Repr(): %T, expected tuple", got)
+				}
+				if len(tuple.Types) != tt.words {
+					t.Errorf("Repr(): %d values, expected %d", len(tuple.Types), tt.words)
+				}
+			}
+			if got.Size() != f.Size() || got.Align() != f.Align() {
+				t.Errorf("Repr(): size %d align %d, expected size %d align %d", got.Size(), got.Align(), f.Size(), f.Align())
+			}
+		})
+	}
+}
+
+func TestFlagsBitOf(t *testing.T) {
+	f := makeFlags(40)
+	for _, tt := range []struct {
+		name string
+		want int
+	}{
+		{"f0", 0},
+		{"f7", 7},
+		{"f39", 39},
+		{"missing", -1},
+	} {
+		if got := f.BitOf(tt.name); got != tt.want {
+			t.Errorf("BitOf(%q): %d, expected %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFlagsMultipleWords(t *testing.T) {
+	f := makeFlags(40)
+	if got, want := f.Size(), uintptr(8); got != want {
+		t.Errorf("Size(): %d, expected %d", got, want)
+	}
+	if got, want := f.Align(), uintptr(4); got != want {
+		t.Errorf("Align(): %d, expected %d", got, want)
+	}
+	flat := f.Flat()
+	if len(flat) != 2 {
+		t.Fatalf("Flat(): %d values, expected 2", len(flat))
+	}
+	for i, v := range flat {
+		if v != (U32{}) {
+			t.Errorf("Flat()[%d]: %v, expected u32", i, v)
+		}
+	}
+	tuple := KindOf[*Tuple](f.Repr())
+	if tuple == nil || len(tuple.Types) != 2 {
+		t.Errorf("Repr(): %v, expected tuple of 2 u32 values", f.Repr())
+	}
+	if bit := f.BitOf("f39"); bit/32 != 1 || bit%32 != 7 {
+		t.Errorf("BitOf(f39): %d, expected word 1, bit 7", bit)
+	}
+}
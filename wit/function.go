@@ -1,6 +1,7 @@
 package wit
 
 import (
+	"errors"
 	"strings"
 )
 
@@ -64,6 +65,15 @@ func (f *Function) Type() Type {
 	}
 }
 
+// ResourceType returns the associated resource [Type] for [Function] f and true,
+// if f is a constructor, method, or static function.
+// If f is a freestanding function, this returns nil and false.
+// See [Function.Type].
+func (f *Function) ResourceType() (Type, bool) {
+	t := f.Type()
+	return t, t != nil
+}
+
 // IsAdmin returns true if [Function] f is an administrative function in the Canonical ABI.
 func (f *Function) IsAdmin() bool {
 	switch {
@@ -120,6 +130,50 @@ func (f *Function) IsStatic() bool {
 	return ok && kind.Type != nil
 }
 
+// HasResults returns true if [Function] f has one or more results.
+func (f *Function) HasResults() bool {
+	return len(f.Results) > 0
+}
+
+// SingleResult returns the single anonymous result of [Function] f and true.
+// If f has no results or one or more named results, it returns false.
+func (f *Function) SingleResult() (Param, bool) {
+	if len(f.Results) == 1 && f.Results[0].Name == "" {
+		return f.Results[0], true
+	}
+	return Param{}, false
+}
+
+// ResultsAsRecord returns an anonymous [Record] with a [Field] for each named result of [Function] f,
+// in order, so a code generator can represent multiple results as a struct.
+// It returns nil if f has no results or a single unnamed result.
+// The returned Record is not part of a [Resolve].
+func (f *Function) ResultsAsRecord() *Record {
+	if _, single := f.SingleResult(); single || len(f.Results) == 0 {
+		return nil
+	}
+	r := &Record{Fields: make([]Field, len(f.Results))}
+	for i, result := range f.Results {
+		r.Fields[i] = Field{Name: result.Name, Type: result.Type}
+	}
+	return r
+}
+
+// validateResults returns an error if the results of [Function] f
+// mix anonymous and named results. A function may have a single
+// anonymous result, or any number of named results.
+func (f *Function) validateResults() error {
+	if len(f.Results) <= 1 {
+		return nil
+	}
+	for _, r := range f.Results {
+		if r.Name == "" {
+			return errors.New("function " + f.Name + " mixes anonymous and named results")
+		}
+	}
+	return nil
+}
+
 func (f *Function) dependsOn(dep Node) bool {
 	if dep == f {
 		return true
@@ -148,6 +202,19 @@ type Param struct {
 	Type Type
 }
 
+// IsOptional returns true if the [Type] of [Param] p is an [Option], or a type alias of an option.
+// A generator may represent an optional parameter as a nilable Go value, such as *T for option<T>,
+// where nil represents none. A nested option, such as option<option<T>>, is also optional, but
+// distinguishes none from some(none), so only the outer option may be represented by nil.
+func (p *Param) IsOptional() bool {
+	td, ok := p.Type.(*TypeDef)
+	if !ok {
+		return false
+	}
+	_, ok = td.Root().Kind.(*Option)
+	return ok
+}
+
 // FunctionKind represents the kind of a WIT [function], which can be one of
 // [Freestanding], [Method], [Static], or [Constructor].
 //
@@ -0,0 +1,112 @@
+package wit
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFunctionResourceType(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:bar;
+
+interface i {
+	resource r {
+		constructor();
+		get: func() -> u32;
+		make: static func() -> r;
+	}
+	free: func();
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := res.Interfaces[0]
+	r := i.TypeDefs.Get("r")
+	tests := []struct {
+		name string
+		want Type
+	}{
+		{"[constructor]r", r},
+		{"[method]r.get", r},
+		{"[static]r.make", r},
+		{"free", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := i.Functions.Get(tt.name)
+			if f == nil {
+				t.Fatalf("function %s not found", tt.name)
+			}
+			got, ok := f.ResourceType()
+			if got != tt.want || ok != (tt.want != nil) {
+				t.Errorf("ResourceType(): %v, %t, expected %v", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestFunctionResultsAsRecord(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:bar;
+
+interface i {
+	none: func();
+	single: func() -> u32;
+	named: func() -> (a: u32);
+	multi: func() -> (a: u32, b: string);
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := res.Interfaces[0]
+	tests := []struct {
+		name   string
+		fields []Field
+	}{
+		{"none", nil},
+		{"single", nil},
+		{"named", []Field{{Name: "a", Type: U32{}}}},
+		{"multi", []Field{{Name: "a", Type: U32{}}, {Name: "b", Type: String{}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := i.Functions.Get(tt.name).ResultsAsRecord()
+			if tt.fields == nil {
+				if r != nil {
+					t.Errorf("ResultsAsRecord(): %v, expected nil", r)
+				}
+				return
+			}
+			if r == nil {
+				t.Fatal("ResultsAsRecord(): nil")
+			}
+			if !reflect.DeepEqual(r.Fields, tt.fields) {
+				t.Errorf("ResultsAsRecord(): %v, expected %v", r.Fields, tt.fields)
+			}
+		})
+	}
+}
+
+func TestParamIsOptional(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:bar;
+
+interface i {
+	type maybe = option<u32>;
+	type also-maybe = maybe;
+	record r { x: option<u32> }
+	f: func(a: u32, b: option<string>, c: option<option<u8>>, d: maybe, e: also-maybe, f: r, g: list<option<u8>>, h: result<u8>);
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := res.Packages[0].Interfaces.Get("i").Functions.Get("f")
+	want := map[string]bool{"a": false, "b": true, "c": true, "d": true, "e": true, "f": false, "g": false, "h": false}
+	for i := range f.Params {
+		p := &f.Params[i]
+		if got := p.IsOptional(); got != want[p.Name] {
+			t.Errorf("IsOptional(%s): %t, expected %t", p.Name, got, want[p.Name])
+		}
+	}
+}
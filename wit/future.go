@@ -10,6 +10,12 @@ type Future struct {
 	Type Type // optional associated Type (can be nil)
 }
 
+// HasPayload returns true if [Future] f has an associated [Type], as in future<T>,
+// or false for a bare future.
+func (f *Future) HasPayload() bool {
+	return f.Type != nil
+}
+
 // Size returns the [ABI byte size] for a [Future].
 // TODO: what is the ABI size of a future?
 //
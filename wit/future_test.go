@@ -0,0 +1,24 @@
+package wit
+
+import "testing"
+
+func TestFuture(t *testing.T) {
+	tests := []struct {
+		f       *Future
+		payload bool
+		wit     string
+	}{
+		{&Future{}, false, "future"},
+		{&Future{Type: U32{}}, true, "future<u32>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.wit, func(t *testing.T) {
+			if got := tt.f.HasPayload(); got != tt.payload {
+				t.Errorf("HasPayload(): %t, expected %t", got, tt.payload)
+			}
+			if got := tt.f.WIT(nil, ""); got != tt.wit {
+				t.Errorf("WIT(): %q, expected %q", got, tt.wit)
+			}
+		})
+	}
+}
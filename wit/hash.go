@@ -0,0 +1,206 @@
+package wit
+
+import (
+	"crypto/sha256"
+	"slices"
+	"strings"
+
+	"go.bytecodealliance.org/wit/ordered"
+)
+
+// Hash returns a SHA-256 fingerprint of the types and functions in [World] w,
+// including the interfaces it imports and exports and the named types they refer to.
+// The fingerprint changes if w changes in a way visible to generated code, such as a new
+// function, a changed type, or a changed @since or @unstable gate. It does not depend on
+// documentation or on the order of imports, exports, interfaces, types, or functions.
+// The order of record fields, variant and enum cases, flags, tuple elements, and
+// function parameters is significant.
+//
+// The fingerprint is computed over a canonical text encoding of w, so it is stable
+// across runs and Go versions.
+func (w *World) Hash() [32]byte {
+	h := &hasher{defs: make(map[string]string)}
+	var b strings.Builder
+	b.WriteString("world ")
+	b.WriteString(w.Package.Name.String())
+	b.WriteString("/")
+	b.WriteString(w.Name)
+	b.WriteString(h.stability(w.Stability))
+	b.WriteString("\n")
+	h.worldItems(&b, "import", &w.Imports)
+	h.worldItems(&b, "export", &w.Exports)
+
+	keys := make([]string, 0, len(h.defs))
+	for k := range h.defs {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		b.WriteString(k)
+		b.WriteString(" = ")
+		b.WriteString(h.defs[k])
+		b.WriteString("\n")
+	}
+	return sha256.Sum256([]byte(b.String()))
+}
+
+// hasher builds the canonical encoding of a [World] for [World.Hash].
+// Named interfaces and types are encoded by name where referred to,
+// and their definitions are recorded once in defs, keyed by qualified name.
+type hasher struct {
+	defs map[string]string
+}
+
+func (h *hasher) worldItems(b *strings.Builder, direction string, items *ordered.Map[string, WorldItem]) {
+	lines := make([]string, 0, items.Len())
+	items.All()(func(name string, item WorldItem) bool {
+		var s string
+		switch item := item.(type) {
+		case *InterfaceRef:
+			s = h.interfaceRef(item.Interface) + h.stability(item.Stability)
+		case *TypeDef:
+			s = h.typeDef(item)
+		case *Function:
+			s = h.function(item)
+		}
+		lines = append(lines, direction+" "+name+": "+s+"\n")
+		return true
+	})
+	slices.Sort(lines)
+	for _, line := range lines {
+		b.WriteString(line)
+	}
+}
+
+// interfaceRef returns the name of named [Interface] i, recording its definition,
+// or the definition of i if it is anonymous.
+func (h *hasher) interfaceRef(i *Interface) string {
+	name := interfaceName(i)
+	if name == "" {
+		return h.interfaceDef(i)
+	}
+	key := "interface " + name
+	if _, ok := h.defs[key]; !ok {
+		h.defs[key] = "" // break cycles
+		h.defs[key] = h.interfaceDef(i)
+	}
+	return key
+}
+
+func (h *hasher) interfaceDef(i *Interface) string {
+	var lines []string
+	i.TypeDefs.All()(func(name string, t *TypeDef) bool {
+		lines = append(lines, "type "+name+": "+h.typeDef(t))
+		return true
+	})
+	i.Functions.All()(func(_ string, f *Function) bool {
+		lines = append(lines, h.function(f))
+		return true
+	})
+	slices.Sort(lines)
+	return "interface" + h.stability(i.Stability) + " {" + strings.Join(lines, "; ") + "}"
+}
+
+// typeDef returns the encoding of the definition of [TypeDef] t.
+func (h *hasher) typeDef(t *TypeDef) string {
+	return h.kind(t.Kind) + h.stability(t.Stability)
+}
+
+func (h *hasher) function(f *Function) string {
+	return "func " + f.Name + h.params(f.Params) + " -> " + h.params(f.Results) + h.stability(f.Stability)
+}
+
+func (h *hasher) params(params []Param) string {
+	s := make([]string, len(params))
+	for i, p := range params {
+		s[i] = p.Name + ": " + h.typ(p.Type)
+	}
+	return "(" + strings.Join(s, ", ") + ")"
+}
+
+// typ returns the encoding of a reference to [Type] t.
+// Named types are referred to by qualified name, and their definitions are recorded.
+// Types in anonymous interfaces are referred to by name, and are defined by the interface.
+func (h *hasher) typ(t Type) string {
+	switch t := t.(type) {
+	case nil:
+		return "_"
+	case *TypeDef:
+		if t.Name == nil {
+			return h.kind(t.Kind)
+		}
+		name := t.QualifiedName()
+		if name == "" {
+			return "type " + *t.Name
+		}
+		key := "type " + name
+		if _, ok := h.defs[key]; !ok {
+			h.defs[key] = "" // break cycles
+			h.defs[key] = h.typeDef(t)
+		}
+		return key
+	}
+	return t.WITKind()
+}
+
+func (h *hasher) kind(kind TypeDefKind) string {
+	switch kind := kind.(type) {
+	case *TypeDef:
+		return h.typ(kind)
+	case *Record:
+		s := make([]string, len(kind.Fields))
+		for i, f := range kind.Fields {
+			s[i] = f.Name + ": " + h.typ(f.Type)
+		}
+		return "record {" + strings.Join(s, ", ") + "}"
+	case *Variant:
+		s := make([]string, len(kind.Cases))
+		for i, c := range kind.Cases {
+			s[i] = c.Name + "(" + h.typ(c.Type) + ")"
+		}
+		return "variant {" + strings.Join(s, ", ") + "}"
+	case *Enum:
+		s := make([]string, len(kind.Cases))
+		for i, c := range kind.Cases {
+			s[i] = c.Name
+		}
+		return "enum {" + strings.Join(s, ", ") + "}"
+	case *Flags:
+		s := make([]string, len(kind.Flags))
+		for i, f := range kind.Flags {
+			s[i] = f.Name
+		}
+		return "flags {" + strings.Join(s, ", ") + "}"
+	case *Tuple:
+		s := make([]string, len(kind.Types))
+		for i, t := range kind.Types {
+			s[i] = h.typ(t)
+		}
+		return "tuple<" + strings.Join(s, ", ") + ">"
+	case *Option:
+		return "option<" + h.typ(kind.Type) + ">"
+	case *List:
+		return "list<" + h.typ(kind.Type) + ">"
+	case *Result:
+		return "result<" + h.typ(kind.OK) + ", " + h.typ(kind.Err) + ">"
+	case *Own:
+		return "own<" + h.typ(kind.Type) + ">"
+	case *Borrow:
+		return "borrow<" + h.typ(kind.Type) + ">"
+	case *Future:
+		return "future<" + h.typ(kind.Type) + ">"
+	case *Stream:
+		return "stream<" + h.typ(kind.Element) + ", " + h.typ(kind.End) + ">"
+	case nil:
+		return "_"
+	}
+	// Resources and primitive types
+	return kind.WITKind()
+}
+
+func (h *hasher) stability(s Stability) string {
+	if s == nil {
+		return ""
+	}
+	return " " + s.String()
+}
@@ -1,7 +1,7 @@
 package wit
 
 import (
-	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/coreos/go-semver/semver"
@@ -42,7 +42,7 @@ func ParseIdent(s string) (Ident, error) {
 		var err error
 		id.Version, err = semver.NewVersion(ver)
 		if err != nil {
-			return id, err
+			return id, fmt.Errorf("%w %q: %w", ErrInvalidPackageName, s, err)
 		}
 	}
 	if hasExt {
@@ -52,12 +52,13 @@ func ParseIdent(s string) (Ident, error) {
 }
 
 // Validate validates id, returning any errors.
+// Returned errors wrap [ErrInvalidPackageName].
 func (id *Ident) Validate() error {
 	switch {
 	case id.Namespace == "":
-		return errors.New("missing package namespace")
+		return fmt.Errorf("%w: missing package namespace", ErrInvalidPackageName)
 	case id.Package == "":
-		return errors.New("missing package name")
+		return fmt.Errorf("%w: missing package name", ErrInvalidPackageName)
 	}
 	return nil
 }
@@ -80,3 +81,31 @@ func (id *Ident) UnversionedString() string {
 	}
 	return id.Namespace + ":" + id.Package + "/" + id.Extension
 }
+
+// Compare returns an integer comparing two [Ident] values, ordered by namespace,
+// package name, extension, and then [SemVer] version. An Ident without a version
+// sorts before any versioned Ident with the same name.
+// The result is 0 if id == other, -1 if id < other, and +1 if id > other.
+// It can be used with [slices.SortFunc].
+//
+// [SemVer]: https://semver.org/
+func (id *Ident) Compare(other *Ident) int {
+	if c := strings.Compare(id.Namespace, other.Namespace); c != 0 {
+		return c
+	}
+	if c := strings.Compare(id.Package, other.Package); c != 0 {
+		return c
+	}
+	if c := strings.Compare(id.Extension, other.Extension); c != 0 {
+		return c
+	}
+	switch {
+	case id.Version == nil && other.Version == nil:
+		return 0
+	case id.Version == nil:
+		return -1
+	case other.Version == nil:
+		return 1
+	}
+	return id.Version.Compare(*other.Version)
+}
@@ -2,6 +2,8 @@ package wit
 
 import (
 	"reflect"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/coreos/go-semver/semver"
@@ -17,6 +19,9 @@ func TestIdent(t *testing.T) {
 		{"wasi:io@0.2.0", Ident{Namespace: "wasi", Package: "io", Version: semver.New("0.2.0")}, false},
 		{"wasi:io/streams", Ident{Namespace: "wasi", Package: "io", Extension: "streams"}, false},
 		{"wasi:io/streams@0.2.0", Ident{Namespace: "wasi", Package: "io", Extension: "streams", Version: semver.New("0.2.0")}, false},
+		{"foo:bar@1.0.0-alpha.1", Ident{Namespace: "foo", Package: "bar", Version: semver.New("1.0.0-alpha.1")}, false},
+		{"foo:bar@1.0.0+build", Ident{Namespace: "foo", Package: "bar", Version: semver.New("1.0.0+build")}, false},
+		{"foo:bar/baz@1.0.0-alpha.1+build", Ident{Namespace: "foo", Package: "bar", Extension: "baz", Version: semver.New("1.0.0-alpha.1+build")}, false},
 
 		// Errors
 		{"", Ident{}, true},
@@ -46,3 +51,127 @@ func TestIdent(t *testing.T) {
 		})
 	}
 }
+
+func TestIdentVersionRoundTrip(t *testing.T) {
+	tests := []string{
+		"foo:bar@1.0.0",
+		"foo:bar@1.0.0-alpha",
+		"foo:bar@1.0.0-alpha.1",
+		"foo:bar@1.0.0+build",
+		"foo:bar@1.0.0-alpha.1+build",
+		"foo:bar@1.0.0-alpha.1+build.5.sha-abc123",
+		"wasi:io@0.2.0-rc-2023-11-10",
+	}
+	for _, s := range tests {
+		t.Run(s, func(t *testing.T) {
+			id, err := ParseIdent(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := id.String(); got != s {
+				t.Errorf("ParseIdent(%q).String(): %q", s, got)
+			}
+
+			// Package declarations and use paths in WIT
+			src := "package foo:root;\n\ninterface a {\n\tuse " + id.Namespace + ":" + id.Package + "/i@" + id.Version.String() + ".{t};\n}\n\n" +
+				"package " + s + " {\n\tinterface i {\n\t\ttype t = u8;\n\t}\n}\n"
+			res, err := ParseWIT(strings.NewReader(src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := res.Packages[0].Name.String(); got != s {
+				t.Errorf("package name: %q, expected %q", got, s)
+			}
+			if got := res.WIT(nil, ""); got != src {
+				t.Errorf("WIT():\n%s\nexpected:\n%s", got, src)
+			}
+		})
+	}
+}
+
+func TestIdentSatisfies(t *testing.T) {
+	tests := []struct {
+		id         string
+		constraint string
+		want       bool
+	}{
+		{"wasi:io", "", true},
+		{"wasi:io", "*", true},
+		{"wasi:io", "^0.2", false},
+		{"wasi:io", ">=0.0.0", false},
+		{"wasi:io@0.2.0", "", true},
+		{"wasi:io@0.2.0", "*", true},
+		{"wasi:io@0.2.0", "0.2.0", true},
+		{"wasi:io@0.2.0", "=0.2.0", true},
+		{"wasi:io@0.2.0", "=0.2.1", false},
+		{"wasi:io@0.2.0", "!=0.2.1", true},
+		{"wasi:io@0.2.0", "^0.2", true},
+		{"wasi:io@0.2.5", "^0.2", true},
+		{"wasi:io@0.3.0", "^0.2", false},
+		{"wasi:io@0.0.3", "^0.0.3", true},
+		{"wasi:io@0.0.4", "^0.0.3", false},
+		{"wasi:io@0.1.0", "^0", true},
+		{"wasi:io@1.0.0", "^0", false},
+		{"wasi:io@1.2.0", "^1.2", true},
+		{"wasi:io@1.9.9", "^1.2", true},
+		{"wasi:io@1.1.0", "^1.2", false},
+		{"wasi:io@2.0.0", "^1.2", false},
+		{"wasi:io@1.2.9", "~1.2", true},
+		{"wasi:io@1.3.0", "~1.2", false},
+		{"wasi:io@1.3.0", "~1", true},
+		{"wasi:io@1.0.0", ">=1.0 <2.0", true},
+		{"wasi:io@1.5.0", ">=1.0, <2.0", true},
+		{"wasi:io@2.0.0", ">=1.0 <2.0", false},
+		{"wasi:io@0.9.0", ">=1.0 <2.0", false},
+		{"wasi:io@2.0.0", ">1.0 <=2.0", true},
+		{"wasi:io@1.0.0", ">1.0", false},
+		{"wasi:io@0.2.0-rc.1", "<0.2.0", true},
+		{"wasi:io@0.2.0-rc.1", ">=0.2.0", false},
+		{"wasi:io@0.2.0", "^", false},
+		{"wasi:io@0.2.0", ">=x.y", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id+" "+tt.constraint, func(t *testing.T) {
+			id, err := ParseIdent(tt.id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := id.Satisfies(tt.constraint)
+			if got != tt.want {
+				t.Errorf("(*Ident).Satisfies(%q): %t, expected %t", tt.constraint, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIdentCompare(t *testing.T) {
+	want := []string{
+		"foo:bar",
+		"wasi:clocks@0.2.0",
+		"wasi:io",
+		"wasi:io@0.2.0-rc-2023-11-10",
+		"wasi:io@0.2.0",
+		"wasi:io@0.2.1",
+		"wasi:io@0.10.0",
+		"wasi:io/error@0.2.0",
+		"wasi:io/streams",
+		"wasi:io/streams@0.2.0",
+	}
+	ids := make([]Ident, len(want))
+	for i, s := range want {
+		var err error
+		ids[len(want)-1-i], err = ParseIdent(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	slices.SortFunc(ids, func(a, b Ident) int { return a.Compare(&b) })
+	for i := range ids {
+		if got := ids[i].String(); got != want[i] {
+			t.Errorf("ids[%d]: got %s, expected %s", i, got, want[i])
+		}
+		if c := ids[i].Compare(&ids[i]); c != 0 {
+			t.Errorf("%s.Compare(%s): got %d, expected 0", want[i], want[i], c)
+		}
+	}
+}
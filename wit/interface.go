@@ -15,10 +15,10 @@ type Interface struct {
 	_typeOwner
 
 	Name      *string
-	TypeDefs  ordered.Map[string, *TypeDef]
-	Functions ordered.Map[string, *Function]
-	Package   *Package  // the Package this Interface belongs to
-	Stability Stability // WIT @since or @unstable (nil if unknown)
+	TypeDefs  ordered.Map[string, *TypeDef]  // in declaration order
+	Functions ordered.Map[string, *Function] // in declaration order
+	Package   *Package                       // the Package this Interface belongs to
+	Stability Stability                      // WIT @since or @unstable (nil if unknown)
 	Docs      Docs
 }
 
@@ -57,6 +57,92 @@ func (i *Interface) AllFunctions() iterate.Seq[*Function] {
 	}
 }
 
+// Freestanding returns the freestanding functions in [Interface] i, in declaration order.
+// Constructors, methods, and static functions of resources are not included.
+func (i *Interface) Freestanding() []*Function {
+	return functionsOfKind[*Freestanding](i)
+}
+
+// Constructors returns the resource constructors in [Interface] i, in declaration order.
+// See [TypeDef.Constructor] for the constructor of a single resource.
+func (i *Interface) Constructors() []*Function {
+	return functionsOfKind[*Constructor](i)
+}
+
+// Methods returns the resource methods in [Interface] i, in declaration order.
+// See [TypeDef.Methods] for the methods of a single resource.
+func (i *Interface) Methods() []*Function {
+	return functionsOfKind[*Method](i)
+}
+
+// Statics returns the static resource functions in [Interface] i, in declaration order.
+// See [TypeDef.StaticFunctions] for the static functions of a single resource.
+func (i *Interface) Statics() []*Function {
+	return functionsOfKind[*Static](i)
+}
+
+func functionsOfKind[K FunctionKind](i *Interface) []*Function {
+	var funcs []*Function
+	i.Functions.All()(func(_ string, f *Function) bool {
+		if _, ok := f.Kind.(K); ok {
+			funcs = append(funcs, f)
+		}
+		return true
+	})
+	return funcs
+}
+
+// ResourceDrop returns the implied [resource-drop] method for a resource type t defined
+// in [Interface] i, which drops an owned handle to t and runs its destructor, if any.
+// If t is a type alias, it is resolved to its [TypeDef.Root].
+// It returns nil if t is not a [Resource], or if the resource is defined in another
+// [Interface] or a [World], in which case its owner provides the resource-drop method.
+//
+// [resource-drop]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#canon-resourcedrop
+func (i *Interface) ResourceDrop(t *TypeDef) *Function {
+	root := t.Root()
+	if root.Owner != i {
+		return nil
+	}
+	return root.ResourceDrop()
+}
+
+// Dependencies returns the interfaces that [Interface] i uses types from, directly or
+// transitively, in dependency order: each interface follows the interfaces it depends on.
+// The result does not include i. If interfaces depend on each other in a cycle,
+// each interface is returned once, in the order it was first reached.
+func (i *Interface) Dependencies() []*Interface {
+	var deps []*Interface
+	visited := map[*Interface]bool{i: true}
+	var visit func(face *Interface)
+	visit = func(face *Interface) {
+		for _, dep := range face.uses() {
+			if visited[dep] {
+				continue
+			}
+			visited[dep] = true
+			visit(dep)
+			deps = append(deps, dep)
+		}
+	}
+	visit(i)
+	return deps
+}
+
+// uses returns the interfaces that [Interface] i directly uses types from, in declaration order.
+func (i *Interface) uses() []*Interface {
+	var uses []*Interface
+	i.TypeDefs.All()(func(_ string, t *TypeDef) bool {
+		if dep, ok := t.Kind.(*TypeDef); ok {
+			if owner, ok := dep.Owner.(*Interface); ok && owner != i && !containsInterface(uses, owner) {
+				uses = append(uses, owner)
+			}
+		}
+		return true
+	})
+	return uses
+}
+
 func (i *Interface) dependsOn(dep Node) bool {
 	if dep == i || dep == i.Package {
 		return true
@@ -0,0 +1,177 @@
+package wit
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestInterfaceResourceDrop(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:bar;
+
+interface a {
+	resource r;
+	type r2 = r;
+	record rec { x: u32 }
+}
+
+interface b {
+	use a.{r};
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := res.Packages[0].Interfaces.Get("a")
+	b := res.Packages[0].Interfaces.Get("b")
+	r := a.TypeDefs.Get("r")
+
+	tests := []struct {
+		i    *Interface
+		t    *TypeDef
+		want string
+	}{
+		{a, r, "[resource-drop]r"},
+		{a, a.TypeDefs.Get("r2"), "[resource-drop]r"},
+		{a, a.TypeDefs.Get("rec"), ""},
+		{b, b.TypeDefs.Get("r"), ""},
+		{b, r, ""},
+	}
+	for _, tt := range tests {
+		t.Run(*tt.i.Name+"."+tt.t.TypeName(), func(t *testing.T) {
+			f := tt.i.ResourceDrop(tt.t)
+			var got string
+			if f != nil {
+				got = f.Name
+				if m, ok := f.Kind.(*Method); !ok || m.Type != r {
+					t.Errorf("ResourceDrop(): Kind %v, expected method of %s", f.Kind, r.TypeName())
+				}
+			}
+			if got != tt.want {
+				t.Errorf("ResourceDrop(): %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInterfaceDependencies(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:bar;
+
+interface a {
+	type t = u32;
+}
+
+interface b {
+	use a.{t};
+	type u = t;
+}
+
+interface c {
+	type v = u8;
+}
+
+interface d {
+	use c.{v};
+	use b.{u};
+	use a.{t};
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := func(faces []*Interface) []string {
+		var s []string
+		for _, i := range faces {
+			s = append(s, *i.Name)
+		}
+		return s
+	}
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"a", nil},
+		{"b", []string{"a"}},
+		{"c", nil},
+		{"d", []string{"c", "a", "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := res.Packages[0].Interfaces.Get(tt.name)
+			if got := names(i.Dependencies()); !slices.Equal(got, tt.want) {
+				t.Errorf("Dependencies(): %v, expected %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInterfaceDependenciesCycle(t *testing.T) {
+	name := func(s string) *string { return &s }
+	a := &Interface{Name: name("a")}
+	b := &Interface{Name: name("b")}
+	ta := &TypeDef{Name: name("ta"), Kind: U32{}, Owner: a}
+	tb := &TypeDef{Name: name("tb"), Kind: U32{}, Owner: b}
+	a.TypeDefs.Set("ta", ta)
+	a.TypeDefs.Set("tb", &TypeDef{Name: name("tb"), Kind: tb, Owner: a})
+	b.TypeDefs.Set("tb", tb)
+	b.TypeDefs.Set("ta", &TypeDef{Name: name("ta"), Kind: ta, Owner: b})
+	if got := a.Dependencies(); len(got) != 1 || got[0] != b {
+		t.Errorf("a.Dependencies(): %v, expected [b]", got)
+	}
+	if got := b.Dependencies(); len(got) != 1 || got[0] != a {
+		t.Errorf("b.Dependencies(): %v, expected [a]", got)
+	}
+}
+
+func TestInterfaceFunctionsByKind(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:bar;
+
+interface i {
+	f1: func();
+	resource r {
+		constructor();
+		get: func() -> u32;
+		make: static func() -> r;
+		set: func(v: u32);
+	}
+	resource s {
+		constructor(v: u32);
+	}
+	f2: func() -> u32;
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := res.Packages[0].Interfaces.Get("i")
+	names := func(funcs []*Function) []string {
+		var s []string
+		for _, f := range funcs {
+			s = append(s, f.Name)
+		}
+		return s
+	}
+
+	tests := []struct {
+		name string
+		got  []*Function
+		want []string
+	}{
+		{"Freestanding", i.Freestanding(), []string{"f1", "f2"}},
+		{"Constructors", i.Constructors(), []string{"[constructor]r", "[constructor]s"}},
+		{"Methods", i.Methods(), []string{"[method]r.get", "[method]r.set"}},
+		{"Statics", i.Statics(), []string{"[static]r.make"}},
+	}
+	var total int
+	for _, tt := range tests {
+		total += len(tt.got)
+		t.Run(tt.name, func(t *testing.T) {
+			if got := names(tt.got); !slices.Equal(got, tt.want) {
+				t.Errorf("%s(): %v, expected %v", tt.name, got, tt.want)
+			}
+		})
+	}
+	if n := i.Functions.Len(); total != n {
+		t.Errorf("accessors returned %d functions, expected %d", total, n)
+	}
+}
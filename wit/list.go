@@ -24,6 +24,24 @@ func (*List) Align() uintptr { return 8 } // [2]int32
 // [flattened]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#flattening
 func (l *List) Flat() []Type { return []Type{PointerTo(l.Type), U32{}} }
 
+// IsByteList returns true if [List] l is a list<u8>, or a list of a type alias of u8.
+// A byte list is stored as a contiguous array of bytes in linear memory, so it can be
+// lifted or lowered with a single copy, and represented in Go as a byte slice.
+func (l *List) IsByteList() bool {
+	t := l.Type
+	for {
+		td, ok := t.(*TypeDef)
+		if !ok {
+			break
+		}
+		if t, ok = td.Kind.(Type); !ok {
+			return false
+		}
+	}
+	_, ok := t.(U8)
+	return ok
+}
+
 func (*List) hasPointer() bool          { return true }
 func (l *List) hasBorrow() bool         { return HasBorrow(l.Type) }
 func (l *List) hasResource() bool       { return HasResource(l.Type) }
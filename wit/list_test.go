@@ -0,0 +1,48 @@
+package wit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestListIsByteList(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:bar;
+
+interface i {
+	type octet = u8;
+	type bytes = list<u8>;
+	type octets = list<octet>;
+	type signed = list<s8>;
+	type words = list<u32>;
+	type nested = list<list<u8>>;
+	record r { x: u8 }
+	type records = list<r>;
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := res.Packages[0].Interfaces.Get("i")
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"bytes", true},
+		{"octets", true},
+		{"signed", false},
+		{"words", false},
+		{"nested", false},
+		{"records", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := KindOf[*List](i.TypeDefs.Get(tt.name))
+			if l == nil {
+				t.Fatalf("%s is not a list", tt.name)
+			}
+			if got := l.IsByteList(); got != tt.want {
+				t.Errorf("IsByteList(): %t, expected %t", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,11 +2,17 @@ package wit
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 )
 
 // LoadJSON loads a [WIT] JSON file from path.
@@ -30,6 +36,104 @@ func LoadWIT(path string) (*Resolve, error) {
 	return loadWIT(path, nil)
 }
 
+// LoadWITDirs loads [WIT] data from one or more root directories by processing it through [wasm-tools].
+// The first directory is the main package. Packages in subsequent directories, and in the deps
+// directory of any root, are made available as dependencies, so WIT in one root can use
+// packages defined in another. If more than one root provides a dependency with the same name,
+// the first one is used.
+// It returns an error if a referenced package is not found in any root.
+// This will fail if wasm-tools is not in $PATH.
+//
+// [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
+// [wasm-tools]: https://crates.io/crates/wasm-tools
+func LoadWITDirs(dirs ...string) (*Resolve, error) {
+	if len(dirs) == 0 {
+		return nil, errors.New("no WIT directories")
+	}
+	tmp, err := os.MkdirTemp("", "wit-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+	if err := stageWITDirs(tmp, dirs); err != nil {
+		return nil, err
+	}
+	res, err := LoadWIT(tmp)
+	if err != nil {
+		return nil, fmt.Errorf("loading WIT from %s: %w", strings.Join(dirs, ", "), err)
+	}
+	return res, nil
+}
+
+// stageWITDirs lays out WIT roots dirs in directory dst in the structure wasm-tools expects:
+// the WIT files of the first root in dst, the WIT files of each subsequent root in dst/deps/root-N,
+// and the contents of each root's deps directory in dst/deps.
+func stageWITDirs(dst string, dirs []string) error {
+	deps := filepath.Join(dst, "deps")
+	for i, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		pkgDir := dst
+		if i > 0 {
+			pkgDir = filepath.Join(deps, "root-"+strconv.Itoa(i))
+		}
+		for _, e := range entries {
+			if e.Type().IsRegular() && filepath.Ext(e.Name()) == ".wit" {
+				if err := copyPath(filepath.Join(pkgDir, e.Name()), filepath.Join(dir, e.Name())); err != nil {
+					return err
+				}
+			}
+		}
+		depEntries, err := os.ReadDir(filepath.Join(dir, "deps"))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		for _, e := range depEntries {
+			target := filepath.Join(deps, e.Name())
+			if _, err := os.Stat(target); err == nil {
+				continue // first root wins
+			}
+			if err := copyPath(target, filepath.Join(dir, "deps", e.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// copyPath copies the file or directory at src to dst, creating parent directories as needed.
+func copyPath(dst, src string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		return os.WriteFile(target, data, 0o644)
+	})
+}
+
 // DecodeWIT decodes [WIT] data from Reader r by processing it through [wasm-tools].
 // This will fail if wasm-tools is not in $PATH.
 //
@@ -39,28 +143,110 @@ func DecodeWIT(r io.Reader) (*Resolve, error) {
 	return loadWIT("", r)
 }
 
+// DecodeWITNamed decodes [WIT] data from Reader r like [DecodeWIT].
+// If the data does not begin with a package declaration, DecodeWITNamed declares
+// a package named name, which allows decoding bare interface or world definitions.
+// An existing package declaration is left unchanged, and name is ignored.
+// It returns an error if name has an extension, e.g. "wasi:io/streams".
+//
+// [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
+func DecodeWITNamed(r io.Reader, name Ident) (*Resolve, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data, err = withPackage(data, name)
+	if err != nil {
+		return nil, err
+	}
+	return loadWIT("", bytes.NewReader(data))
+}
+
+// withPackage returns WIT data with a package declaration for name,
+// unless data already begins with one. The declaration is prepended on the
+// first line, so line numbers in error messages match the original data.
+func withPackage(data []byte, name Ident) ([]byte, error) {
+	p := &parser{src: string(data), line: 1}
+	p.next()
+	if p.is("package") {
+		return data, nil
+	}
+	if name.Extension != "" {
+		return nil, fmt.Errorf("package name %s has an extension", name.String())
+	}
+	if name.Namespace == "" || name.Package == "" {
+		return nil, errors.New("missing package name")
+	}
+	header := "package " + name.String() + "; "
+	return append([]byte(header), data...), nil
+}
+
+// EncodeWasm writes the binary WebAssembly encoding of [Resolve] r to w, a component type
+// consumed by component toolchains, by processing the [WIT] text format of r through [wasm-tools].
+// As with wasm-tools, the main package of r, the one that no other package depends on, is encoded,
+// along with the packages it depends on. This will fail if wasm-tools is not in $PATH.
+//
+// [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
+// [wasm-tools]: https://crates.io/crates/wasm-tools
+func (r *Resolve) EncodeWasm(w io.Writer) error {
+	wasmTools, err := exec.LookPath("wasm-tools")
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrWasmToolsNotFound, err)
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(wasmTools, "component", "wit", "--wasm", "--all-features")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Stdin = strings.NewReader(r.WIT(nil, ""))
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("wasm-tools: %w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	_, err = stdout.WriteTo(w)
+	return err
+}
+
 // loadWIT loads WIT data from path or reader by processing it through wasm-tools.
 // It accepts either a path or an io.Reader as input, but not both.
 // If the path is not "" and "-", it will be used as the input file.
 // Otherwise, the reader will be used as the input.
 func loadWIT(path string, reader io.Reader) (*Resolve, error) {
+	data, err := witJSON(path, reader)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeJSON(bytes.NewReader(data))
+}
+
+// witJSON returns the JSON representation of WIT data from path or reader
+// by processing it through wasm-tools. See [loadWIT] for details.
+func witJSON(path string, reader io.Reader) ([]byte, error) {
+	return witJSONContext(context.Background(), path, reader, nil)
+}
+
+// witJSONContext is like witJSON, but kills the wasm-tools process if ctx is done.
+// If wasm-tools succeeds and w is non-nil, anything wasm-tools wrote to stderr is copied to w.
+// Any extraArgs are appended to the wasm-tools arguments; see [wasmToolsArgs].
+func witJSONContext(ctx context.Context, path string, reader io.Reader, w io.Writer, extraArgs ...string) ([]byte, error) {
 	if path != "" && reader != nil {
 		return nil, errors.New("cannot set both path and reader; provide only one")
 	}
 
-	wasmTools, err := exec.LookPath("wasm-tools")
+	cmdArgs, err := wasmToolsArgs(path, extraArgs)
 	if err != nil {
 		return nil, err
 	}
 
-	var stdout, stderr bytes.Buffer
-
-	cmdArgs := []string{"component", "wit", "-j", "--all-features"}
-	if path != "" {
-		cmdArgs = append(cmdArgs, path)
+	wasmTools, err := exec.LookPath("wasm-tools")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrWasmToolsNotFound, err)
 	}
 
-	cmd := exec.Command(wasmTools, cmdArgs...)
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, wasmTools, cmdArgs...)
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 	cmd.Stdin = reader
@@ -69,6 +255,37 @@ func loadWIT(path string, reader io.Reader) (*Resolve, error) {
 		fmt.Fprint(os.Stderr, stderr.String())
 		return nil, err
 	}
+	if w != nil {
+		if _, err := stderr.WriteTo(w); err != nil {
+			return nil, err
+		}
+	}
+
+	return stdout.Bytes(), nil
+}
 
-	return DecodeJSON(&stdout)
+// wasmToolsBaseArgs are the arguments to wasm-tools that print WIT as JSON with all features enabled.
+var wasmToolsBaseArgs = []string{"component", "wit", "-j", "--all-features"}
+
+// wasmToolsArgs returns the arguments to run wasm-tools on path, or on stdin if path is empty:
+// the base arguments "component wit -j --all-features", then extra, then path.
+// If extra selects features with --features, --all-features is omitted.
+// It returns an error if extra contains an argument that changes the output
+// of wasm-tools from JSON on stdout, such as --wasm or --output.
+func wasmToolsArgs(path string, extra []string) ([]string, error) {
+	args := slices.Clone(wasmToolsBaseArgs)
+	for _, arg := range extra {
+		name, _, _ := strings.Cut(arg, "=")
+		switch name {
+		case "-j", "--json", "-w", "--wasm", "-t", "--wat", "-o", "--output", "--out-dir":
+			return nil, fmt.Errorf("wasm-tools argument %s conflicts with JSON output", arg)
+		case "--features":
+			args = slices.DeleteFunc(args, func(s string) bool { return s == "--all-features" })
+		}
+	}
+	args = append(args, extra...)
+	if path != "" {
+		args = append(args, path)
+	}
+	return args, nil
 }
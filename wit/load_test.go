@@ -0,0 +1,192 @@
+package wit
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWithPackage(t *testing.T) {
+	tests := []struct {
+		name    string
+		pkg     string
+		src     string
+		want    string
+		wantErr bool
+	}{
+		{"bare interface", "foo:bar", "interface i {}", "foo:bar", false},
+		{"versioned", "foo:bar@1.0.0", "interface i {}", "foo:bar@1.0.0", false},
+		{"existing", "foo:bar", "package a:b;\ninterface i {}", "a:b", false},
+		{"existing with docs", "foo:bar", "// comment\n/// docs\npackage a:b;\ninterface i {}", "a:b", false},
+		{"extension", "foo:bar/baz", "interface i {}", "", true},
+		{"empty name", "", "interface i {}", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var name Ident
+			if tt.pkg != "" {
+				var err error
+				name, err = ParseIdent(tt.pkg)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			data, err := withPackage([]byte(tt.src), name)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("withPackage: expected error, got %q", data)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if strings.Count(string(data), "\n") != strings.Count(tt.src, "\n") {
+				t.Errorf("withPackage changed the number of lines:\n%s", data)
+			}
+			res, err := ParseWIT(strings.NewReader(string(data)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := res.Packages[0].Name.String(); got != tt.want {
+				t.Errorf("package: %s, expected %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStageWITDirs(t *testing.T) {
+	write := func(path, s string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(s), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a, b := t.TempDir(), t.TempDir()
+	write(filepath.Join(a, "world.wit"), "package foo:a;")
+	write(filepath.Join(a, "README.md"), "not WIT")
+	write(filepath.Join(a, "deps/io/streams.wit"), "package wasi:io; // from a")
+	write(filepath.Join(b, "types.wit"), "package foo:b;")
+	write(filepath.Join(b, "deps/io/streams.wit"), "package wasi:io; // from b")
+	write(filepath.Join(b, "deps/clocks.wit"), "package wasi:clocks;")
+
+	dst := t.TempDir()
+	if err := stageWITDirs(dst, []string{a, b}); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"world.wit":             "package foo:a;",
+		"deps/root-1/types.wit": "package foo:b;",
+		"deps/io/streams.wit":   "package wasi:io; // from a",
+		"deps/clocks.wit":       "package wasi:clocks;",
+	}
+	got := make(map[string]string)
+	err := filepath.WalkDir(dst, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		rel, _ := filepath.Rel(dst, path)
+		got[filepath.ToSlash(rel)] = string(data)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("stageWITDirs:\n%v\nexpected:\n%v", got, want)
+	}
+}
+
+func TestLoadWITDirsEmpty(t *testing.T) {
+	if _, err := LoadWITDirs(); err == nil {
+		t.Error("LoadWITDirs(): expected error with no directories")
+	}
+}
+
+func TestWasmToolsArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		extra   []string
+		want    string
+		wantErr bool
+	}{
+		{"stdin", "", nil, "component wit -j --all-features", false},
+		{"path", "wit", nil, "component wit -j --all-features wit", false},
+		{"extra", "wit", []string{"--skip-validation"}, "component wit -j --all-features --skip-validation wit", false},
+		{"features", "", []string{"--features", "a,b"}, "component wit -j --features a,b", false},
+		{"features=", "", []string{"--features=a"}, "component wit -j --features=a", false},
+		{"wasm", "", []string{"--wasm"}, "", true},
+		{"output", "", []string{"--output=out.json"}, "", true},
+		{"json", "", []string{"-j"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args, err := wasmToolsArgs(tt.path, tt.extra)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("wasmToolsArgs: %v, expected error: %t", err, tt.wantErr)
+			}
+			if got := strings.Join(args, " "); got != tt.want {
+				t.Errorf("wasmToolsArgs: %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveEncodeWasm(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:bar@0.1.0;
+
+interface i {
+	record r { x: u32 }
+	f: func(r: r) -> string;
+}
+
+world w {
+	export i;
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("wasm-tools not found", func(t *testing.T) {
+		t.Setenv("PATH", "")
+		var b bytes.Buffer
+		if err := res.EncodeWasm(&b); !errors.Is(err, ErrWasmToolsNotFound) {
+			t.Errorf("EncodeWasm: %v, expected ErrWasmToolsNotFound", err)
+		}
+		if b.Len() != 0 {
+			t.Errorf("EncodeWasm: wrote %d bytes, expected none", b.Len())
+		}
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		if _, err := exec.LookPath("wasm-tools"); err != nil {
+			t.Skip("wasm-tools not found")
+		}
+		var b bytes.Buffer
+		if err := res.EncodeWasm(&b); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.HasPrefix(b.Bytes(), []byte("\x00asm")) {
+			t.Fatalf("EncodeWasm: output does not begin with the wasm magic number: % x", b.Bytes()[:min(8, b.Len())])
+		}
+		got, err := DecodeWIT(&b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := got.WIT(nil, ""), res.WIT(nil, ""); got != want {
+			t.Errorf("WIT after EncodeWasm and DecodeWIT:\n%s\nexpected:\n%s", got, want)
+		}
+	})
+}
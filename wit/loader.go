@@ -0,0 +1,234 @@
+package wit
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Loader loads [WIT] data through [wasm-tools], caching the results by a hash of the input content.
+// Loading identical input again returns a new [Resolve] decoded from the cached wasm-tools output,
+// without running wasm-tools. Each returned Resolve is independent of the cache and of other
+// returned values, so callers may modify it.
+//
+// The zero value is ready to use, with an unbounded cache. A Loader is safe for concurrent use.
+//
+// [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
+// [wasm-tools]: https://crates.io/crates/wasm-tools
+type Loader struct {
+	// MaxEntries is the maximum number of cached entries. If 0, the cache is unbounded.
+	// When the cache is full, the least recently used entry is evicted.
+	MaxEntries int
+
+	// Timeout is the maximum duration of each run of wasm-tools. If 0, there is no timeout.
+	// If wasm-tools runs longer than Timeout, it is killed, and the Loader returns an error
+	// that wraps [ErrWasmToolsTimeout]. Timeouts are not retried.
+	Timeout time.Duration
+
+	// Retries is the number of times to retry running wasm-tools if it fails transiently,
+	// for example if it is killed by a signal from the operating system when out of memory.
+	// Errors reported by wasm-tools, such as invalid WIT, are not retried.
+	Retries int
+
+	// StrictWarnings causes the Loader to return an error wrapping [ErrWasmToolsWarnings]
+	// if wasm-tools writes warnings to stderr, even if it succeeds. The error contains the warnings.
+	// By default, warnings from a successful run of wasm-tools are ignored.
+	// Warnings are cached with the output of wasm-tools, so a cache hit reports the same warnings.
+	StrictWarnings bool
+
+	// ExtraArgs are appended to the arguments of each run of wasm-tools,
+	// after the base arguments "component wit -j --all-features" and before the input path, if any.
+	// For example, ExtraArgs of ["--features", "clocks-timezone"] enables only the named
+	// feature gates rather than all features, and --all-features is omitted.
+	// Arguments that change the output of wasm-tools from JSON on stdout, such as
+	// --wasm, --wat, or --output, are rejected with an error.
+	// ExtraArgs are part of the cache key, so changing them does not return stale results.
+	ExtraArgs []string
+
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]loaderEntry
+	recent  [][sha256.Size]byte                                                                        // least recently used first
+	witJSON func(ctx context.Context, path string, reader io.Reader, stderr io.Writer) ([]byte, error) // for testing; defaults to witJSONContext
+}
+
+// loaderEntry is the cached output of a successful run of wasm-tools.
+type loaderEntry struct {
+	data     []byte
+	warnings []byte // written to stderr
+}
+
+// LoadWIT loads [WIT] data from path, which may be a file or a directory, like [LoadWIT].
+// If path is a directory, the cache key includes the name and contents of every file within it.
+//
+// [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
+func (l *Loader) LoadWIT(path string) (*Resolve, error) {
+	key, err := hashPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return l.load(key, path, nil)
+}
+
+// DecodeWIT decodes [WIT] data from Reader r, like [DecodeWIT].
+//
+// [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
+func (l *Loader) DecodeWIT(r io.Reader) (*Resolve, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return l.load(sha256.Sum256(data), "", data)
+}
+
+// Len returns the number of entries in the cache.
+func (l *Loader) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.entries)
+}
+
+// Clear removes all entries from the cache.
+func (l *Loader) Clear() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.entries = nil
+	l.recent = nil
+}
+
+func (l *Loader) load(key [sha256.Size]byte, path string, input []byte) (*Resolve, error) {
+	if _, err := wasmToolsArgs("", l.ExtraArgs); err != nil {
+		return nil, err
+	}
+	if len(l.ExtraArgs) > 0 {
+		h := sha256.New()
+		h.Write(key[:])
+		for _, arg := range l.ExtraArgs {
+			h.Write(binary.AppendUvarint(nil, uint64(len(arg))))
+			h.Write([]byte(arg))
+		}
+		h.Sum(key[:0])
+	}
+	e, ok := l.get(key)
+	if !ok {
+		var err error
+		for attempt := 0; ; attempt++ {
+			e, err = l.run(path, input)
+			if err == nil || attempt >= l.Retries || !isTransient(err) {
+				break
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		l.put(key, e)
+	}
+	if l.StrictWarnings && len(bytes.TrimSpace(e.warnings)) > 0 {
+		return nil, fmt.Errorf("%w:\n%s", ErrWasmToolsWarnings, bytes.TrimSpace(e.warnings))
+	}
+	return DecodeJSON(bytes.NewReader(e.data))
+}
+
+// run runs wasm-tools once on path or input, subject to l.Timeout.
+func (l *Loader) run(path string, input []byte) (loaderEntry, error) {
+	f := l.witJSON
+	if f == nil {
+		f = func(ctx context.Context, path string, reader io.Reader, stderr io.Writer) ([]byte, error) {
+			return witJSONContext(ctx, path, reader, stderr, l.ExtraArgs...)
+		}
+	}
+	ctx := context.Background()
+	if l.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.Timeout)
+		defer cancel()
+	}
+	var reader io.Reader
+	if input != nil {
+		reader = bytes.NewReader(input)
+	}
+	var stderr bytes.Buffer
+	data, err := f(ctx, path, reader, &stderr)
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return loaderEntry{}, fmt.Errorf("%w after %v", ErrWasmToolsTimeout, l.Timeout)
+	}
+	return loaderEntry{data: data, warnings: stderr.Bytes()}, err
+}
+
+// isTransient reports whether err indicates wasm-tools was terminated by a signal,
+// rather than exiting with an error.
+func isTransient(err error) bool {
+	var exitErr *exec.ExitError
+	return errors.As(err, &exitErr) && !exitErr.Exited()
+}
+
+func (l *Loader) get(key [sha256.Size]byte) (loaderEntry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	e, ok := l.entries[key]
+	if ok {
+		l.touch(key)
+	}
+	return e, ok
+}
+
+func (l *Loader) put(key [sha256.Size]byte, e loaderEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.entries == nil {
+		l.entries = make(map[[sha256.Size]byte]loaderEntry)
+	}
+	if _, ok := l.entries[key]; ok {
+		l.touch(key)
+		return
+	}
+	l.entries[key] = e
+	l.recent = append(l.recent, key)
+	for l.MaxEntries > 0 && len(l.recent) > l.MaxEntries {
+		delete(l.entries, l.recent[0])
+		l.recent = l.recent[1:]
+	}
+}
+
+// touch marks key as the most recently used entry. The caller must hold l.mu.
+func (l *Loader) touch(key [sha256.Size]byte) {
+	i := slices.Index(l.recent, key)
+	l.recent = append(slices.Delete(l.recent, i, i+1), key)
+}
+
+// hashPath returns a hash of the file at path, or of the names and contents
+// of each file in the directory at path.
+func hashPath(path string) ([sha256.Size]byte, error) {
+	h := sha256.New()
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(path, p)
+		if err != nil {
+			return err
+		}
+		for _, b := range [][]byte{[]byte(filepath.ToSlash(rel)), data} {
+			h.Write(binary.AppendUvarint(nil, uint64(len(b))))
+			h.Write(b)
+		}
+		return nil
+	})
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	return sum, err
+}
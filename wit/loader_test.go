@@ -0,0 +1,251 @@
+package wit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoaderCache(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join(testdataPath, "wasi/cli.wit.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var calls int
+	l := &Loader{
+		MaxEntries: 2,
+		witJSON: func(_ context.Context, path string, reader io.Reader, _ io.Writer) ([]byte, error) {
+			calls++
+			return data, nil
+		},
+	}
+
+	decode := func(s string) *Resolve {
+		t.Helper()
+		res, err := l.DecodeWIT(strings.NewReader(s))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	a := decode("a")
+	b := decode("a")
+	if calls != 1 {
+		t.Errorf("calls: %d, expected 1", calls)
+	}
+	if a == b || a.Worlds[0] == b.Worlds[0] {
+		t.Errorf("DecodeWIT returned aliased Resolves for a cache hit")
+	}
+
+	decode("b")
+	decode("a") // a is now the most recently used
+	decode("c") // evicts b
+	if calls != 3 {
+		t.Errorf("calls: %d, expected 3", calls)
+	}
+	if got, want := l.Len(), 2; got != want {
+		t.Errorf("Len(): %d, expected %d", got, want)
+	}
+	decode("a")
+	if calls != 3 {
+		t.Errorf("calls: %d, expected 3 after hit on a", calls)
+	}
+	decode("b")
+	if calls != 4 {
+		t.Errorf("calls: %d, expected 4 after miss on evicted b", calls)
+	}
+
+	l.Clear()
+	if got := l.Len(); got != 0 {
+		t.Errorf("Len() after Clear(): %d, expected 0", got)
+	}
+	decode("a")
+	if calls != 5 {
+		t.Errorf("calls: %d, expected 5 after Clear()", calls)
+	}
+}
+
+func TestLoaderLoadWITDir(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "world.wit")
+	write := func(s string) {
+		if err := os.WriteFile(path, []byte(s), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var calls int
+	l := &Loader{
+		witJSON: func(_ context.Context, path string, reader io.Reader, _ io.Writer) ([]byte, error) {
+			calls++
+			return []byte(`{"worlds":[],"interfaces":[],"types":[],"packages":[]}`), nil
+		},
+	}
+	load := func() {
+		t.Helper()
+		if _, err := l.LoadWIT(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("package a:b;")
+	load()
+	load()
+	if calls != 1 {
+		t.Errorf("calls: %d, expected 1", calls)
+	}
+	write("package a:c;")
+	load()
+	if calls != 2 {
+		t.Errorf("calls: %d, expected 2 after modifying directory contents", calls)
+	}
+}
+
+func TestLoaderTimeout(t *testing.T) {
+	var calls int
+	l := &Loader{
+		Timeout: 10 * time.Millisecond,
+		Retries: 2,
+		witJSON: func(ctx context.Context, path string, reader io.Reader, _ io.Writer) ([]byte, error) {
+			calls++
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+	_, err := l.DecodeWIT(strings.NewReader("package a:b;"))
+	if !errors.Is(err, ErrWasmToolsTimeout) {
+		t.Errorf("DecodeWIT: %v, expected ErrWasmToolsTimeout", err)
+	}
+	if calls != 1 {
+		t.Errorf("calls: %d, expected 1 (timeouts are not retried)", calls)
+	}
+}
+
+func TestLoaderRetries(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+	killed := exec.Command("sh", "-c", "kill -9 $$").Run()
+	if !isTransient(killed) {
+		t.Skipf("cannot produce a process killed by a signal: %v", killed)
+	}
+	failed := exec.Command("sh", "-c", "exit 1").Run()
+	if isTransient(failed) {
+		t.Fatalf("isTransient(%v): true, expected false", failed)
+	}
+
+	tests := []struct {
+		name      string
+		retries   int
+		errs      []error
+		wantCalls int
+		wantErr   bool
+	}{
+		{"success after retry", 2, []error{killed, nil}, 2, false},
+		{"retries exhausted", 1, []error{killed, killed, nil}, 2, true},
+		{"exit error not retried", 2, []error{failed, nil}, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			l := &Loader{
+				Retries: tt.retries,
+				witJSON: func(_ context.Context, path string, reader io.Reader, _ io.Writer) ([]byte, error) {
+					err := tt.errs[calls]
+					calls++
+					if err != nil {
+						return nil, err
+					}
+					return []byte(`{"worlds":[],"interfaces":[],"types":[],"packages":[]}`), nil
+				},
+			}
+			_, err := l.DecodeWIT(strings.NewReader("package a:b;"))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DecodeWIT: %v, expected error: %t", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("calls: %d, expected %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestLoaderStrictWarnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		strict   bool
+		warnings string
+		wantErr  bool
+	}{
+		{"lenient", false, "warning: deprecated\n", false},
+		{"strict without warnings", true, "", false},
+		{"strict whitespace", true, "\n", false},
+		{"strict with warnings", true, "warning: deprecated\n", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			l := &Loader{
+				StrictWarnings: tt.strict,
+				witJSON: func(_ context.Context, path string, reader io.Reader, stderr io.Writer) ([]byte, error) {
+					calls++
+					io.WriteString(stderr, tt.warnings)
+					return []byte(`{"worlds":[],"interfaces":[],"types":[],"packages":[]}`), nil
+				},
+			}
+			for range 2 {
+				_, err := l.DecodeWIT(strings.NewReader("package a:b;"))
+				if tt.wantErr {
+					if !errors.Is(err, ErrWasmToolsWarnings) {
+						t.Errorf("DecodeWIT: %v, expected ErrWasmToolsWarnings", err)
+					} else if !strings.Contains(err.Error(), "warning: deprecated") {
+						t.Errorf("DecodeWIT: %v, expected error to contain warnings", err)
+					}
+				} else if err != nil {
+					t.Errorf("DecodeWIT: %v", err)
+				}
+			}
+			if calls != 1 {
+				t.Errorf("calls: %d, expected 1", calls)
+			}
+		})
+	}
+}
+
+func TestLoaderExtraArgs(t *testing.T) {
+	var calls int
+	l := &Loader{
+		witJSON: func(_ context.Context, path string, reader io.Reader, _ io.Writer) ([]byte, error) {
+			calls++
+			return []byte(`{"worlds":[],"interfaces":[],"types":[],"packages":[]}`), nil
+		},
+	}
+	decode := func() error {
+		_, err := l.DecodeWIT(strings.NewReader("package a:b;"))
+		return err
+	}
+	for _, args := range [][]string{nil, {"--features", "a"}, {"--features", "b"}, {"--features", "a"}} {
+		l.ExtraArgs = args
+		if err := decode(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls != 3 {
+		t.Errorf("calls: %d, expected 3", calls)
+	}
+
+	l.ExtraArgs = []string{"--wasm"}
+	if err := decode(); err == nil {
+		t.Error("DecodeWIT with --wasm: nil error, expected error")
+	}
+	if calls != 3 {
+		t.Errorf("calls: %d, expected 3", calls)
+	}
+}
@@ -0,0 +1,234 @@
+package wit
+
+import (
+	"fmt"
+
+	"go.bytecodealliance.org/wit/ordered"
+)
+
+// Merge combines the packages, worlds, interfaces, and types in resolves into a single [Resolve],
+// in order, so types and interfaces in one Resolve can refer to packages in another.
+// A package present in more than one Resolve, identified by name and version, is included once,
+// and references to its contents are updated to refer to the first instance of the package.
+// Merge returns an error if the definitions of a package differ, as reported by [Diff].
+//
+// The contents of resolves are reused, not copied, and may be modified by Merge.
+// The resolves passed to Merge should not be used after calling Merge.
+func Merge(resolves ...*Resolve) (*Resolve, error) {
+	m := &merger{
+		res:      &Resolve{},
+		packages: make(map[string]*Package),
+		ifaces:   make(map[*Interface]*Interface),
+		types:    make(map[*TypeDef]*TypeDef),
+	}
+	for _, r := range resolves {
+		if err := m.merge(r); err != nil {
+			return nil, err
+		}
+	}
+	return m.res, nil
+}
+
+type merger struct {
+	res      *Resolve
+	packages map[string]*Package       // merged packages by name
+	ifaces   map[*Interface]*Interface // duplicate interfaces to merged interfaces
+	types    map[*TypeDef]*TypeDef     // duplicate types to merged types
+	visited  map[*TypeDef]bool         // anonymous types referenced by merged items
+}
+
+func (m *merger) merge(r *Resolve) error {
+	dups := make(map[*Package]bool)
+	for _, p := range r.Packages {
+		name := p.Name.String()
+		prev, ok := m.packages[name]
+		if !ok {
+			m.packages[name] = p
+			m.res.Packages = append(m.res.Packages, p)
+			continue
+		}
+		if changes := Diff(packageResolve(prev), packageResolve(p)); len(changes) > 0 {
+			return fmt.Errorf("conflicting definitions of package %s: %s", name, changes[0].String())
+		}
+		dups[p] = true
+		m.mapPackage(prev, p)
+	}
+
+	m.visited = make(map[*TypeDef]bool)
+	for _, i := range r.Interfaces {
+		if !dups[i.Package] {
+			m.res.Interfaces = append(m.res.Interfaces, i)
+			m.rewriteInterface(i)
+		}
+	}
+	for _, w := range r.Worlds {
+		if !dups[w.Package] {
+			m.res.Worlds = append(m.res.Worlds, w)
+			m.rewriteWorld(w)
+		}
+	}
+	for _, t := range r.TypeDefs {
+		if _, ok := m.types[t]; !ok && t.Owner != nil {
+			t.Kind = m.rewriteKind(t.Kind)
+		}
+	}
+	for _, t := range r.TypeDefs {
+		if _, ok := m.types[t]; ok {
+			continue
+		}
+		if t.Owner == nil && len(dups) > 0 && !m.visited[t] {
+			// Only referenced by a duplicate package
+			continue
+		}
+		m.res.TypeDefs = append(m.res.TypeDefs, t)
+	}
+	return nil
+}
+
+// packageResolve returns a [Resolve] containing the worlds and interfaces in [Package] p.
+func packageResolve(p *Package) *Resolve {
+	r := &Resolve{Packages: []*Package{p}}
+	p.Interfaces.All()(func(_ string, i *Interface) bool {
+		r.Interfaces = append(r.Interfaces, i)
+		return true
+	})
+	p.Worlds.All()(func(_ string, w *World) bool {
+		r.Worlds = append(r.Worlds, w)
+		return true
+	})
+	return r
+}
+
+// mapPackage maps the interfaces and types in duplicate [Package] dup to those in p.
+func (m *merger) mapPackage(p, dup *Package) {
+	dup.Interfaces.All()(func(name string, i *Interface) bool {
+		m.mapInterface(p.Interfaces.Get(name), i)
+		return true
+	})
+	dup.Worlds.All()(func(name string, w *World) bool {
+		pw := p.Worlds.Get(name)
+		mapItems := func(items, dupItems *ordered.Map[string, WorldItem]) {
+			dupItems.All()(func(key string, item WorldItem) bool {
+				switch item := item.(type) {
+				case *InterfaceRef:
+					if ref, ok := items.Get(key).(*InterfaceRef); ok && item.Interface.Name == nil {
+						m.mapInterface(ref.Interface, item.Interface)
+					}
+				case *TypeDef:
+					if t, ok := items.Get(key).(*TypeDef); ok {
+						m.types[item] = t
+					}
+				}
+				return true
+			})
+		}
+		mapItems(&pw.Imports, &w.Imports)
+		mapItems(&pw.Exports, &w.Exports)
+		return true
+	})
+}
+
+func (m *merger) mapInterface(i, dup *Interface) {
+	m.ifaces[dup] = i
+	dup.TypeDefs.All()(func(name string, t *TypeDef) bool {
+		m.types[t] = i.TypeDefs.Get(name)
+		return true
+	})
+}
+
+func (m *merger) rewriteInterface(i *Interface) {
+	i.Functions.All()(func(_ string, f *Function) bool {
+		m.rewriteFunction(f)
+		return true
+	})
+}
+
+func (m *merger) rewriteWorld(w *World) {
+	f := func(_ string, item WorldItem) bool {
+		switch item := item.(type) {
+		case *InterfaceRef:
+			if i, ok := m.ifaces[item.Interface]; ok {
+				item.Interface = i
+			}
+		case *Function:
+			m.rewriteFunction(item)
+		}
+		return true
+	}
+	w.Imports.All()(f)
+	w.Exports.All()(f)
+}
+
+func (m *merger) rewriteFunction(f *Function) {
+	for i := range f.Params {
+		f.Params[i].Type = m.rewriteType(f.Params[i].Type)
+	}
+	for i := range f.Results {
+		f.Results[i].Type = m.rewriteType(f.Results[i].Type)
+	}
+	switch k := f.Kind.(type) {
+	case *Method:
+		k.Type = m.rewriteType(k.Type)
+	case *Static:
+		k.Type = m.rewriteType(k.Type)
+	case *Constructor:
+		k.Type = m.rewriteType(k.Type)
+	}
+}
+
+// rewriteType returns the merged type for t, which may be nil.
+// Anonymous types are rewritten in place.
+func (m *merger) rewriteType(t Type) Type {
+	td, ok := t.(*TypeDef)
+	if !ok {
+		return t
+	}
+	if merged, ok := m.types[td]; ok {
+		return merged
+	}
+	if td.Owner == nil && !m.visited[td] {
+		m.visited[td] = true
+		td.Kind = m.rewriteKind(td.Kind)
+	}
+	return td
+}
+
+func (m *merger) rewriteTypeDef(t *TypeDef) *TypeDef {
+	return m.rewriteType(t).(*TypeDef)
+}
+
+func (m *merger) rewriteKind(k TypeDefKind) TypeDefKind {
+	switch k := k.(type) {
+	case *TypeDef:
+		return m.rewriteTypeDef(k)
+	case *Record:
+		for i := range k.Fields {
+			k.Fields[i].Type = m.rewriteType(k.Fields[i].Type)
+		}
+	case *Variant:
+		for i := range k.Cases {
+			k.Cases[i].Type = m.rewriteType(k.Cases[i].Type)
+		}
+	case *Tuple:
+		for i := range k.Types {
+			k.Types[i] = m.rewriteType(k.Types[i])
+		}
+	case *Option:
+		k.Type = m.rewriteType(k.Type)
+	case *List:
+		k.Type = m.rewriteType(k.Type)
+	case *Result:
+		k.OK = m.rewriteType(k.OK)
+		k.Err = m.rewriteType(k.Err)
+	case *Own:
+		k.Type = m.rewriteTypeDef(k.Type)
+	case *Borrow:
+		k.Type = m.rewriteTypeDef(k.Type)
+	case *Future:
+		k.Type = m.rewriteType(k.Type)
+	case *Stream:
+		k.Element = m.rewriteType(k.Element)
+		k.End = m.rewriteType(k.End)
+	}
+	return k
+}
@@ -0,0 +1,127 @@
+package wit
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	parse := func(s string) *Resolve {
+		t.Helper()
+		res, err := ParseWIT(strings.NewReader(s))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	const shared = `
+package shared:types@1.0.0 {
+	interface types {
+		record point { x: u32, y: u32 }
+		resource blob;
+	}
+}
+`
+	a := parse(`package foo:a;
+
+interface api {
+	use shared:types/types@1.0.0.{point};
+	get: func() -> list<point>;
+}
+` + shared)
+	b := parse(`package foo:b;
+
+interface api {
+	use shared:types/types@1.0.0.{point, blob};
+	set: func(p: option<point>, b: borrow<blob>);
+}
+
+world w {
+	import api;
+}
+` + shared)
+
+	bTypes := b.Packages[0].Interfaces.Get("types")
+	if bTypes == nil {
+		t.Fatal("shared:types/types not found")
+	}
+
+	res, err := Merge(a, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, p := range res.Packages {
+		names = append(names, p.Name.String())
+	}
+	if got, want := strings.Join(names, " "), "shared:types@1.0.0 foo:a foo:b"; got != want {
+		t.Errorf("Merge(): packages %q, expected %q", got, want)
+	}
+	if got, want := len(res.Interfaces), 3; got != want {
+		t.Errorf("Merge(): %d interfaces, expected %d", got, want)
+	}
+
+	// References to the duplicate package must refer to the merged package
+	types := res.Packages[0].Interfaces.Get("types")
+	point := types.TypeDefs.Get("point")
+	blob := types.TypeDefs.Get("blob")
+	api := res.Packages[2].Interfaces.Get("api")
+	if got := api.TypeDefs.Get("point").Kind; got != point {
+		t.Errorf("foo:b/api#point refers to %p, expected %p", got, point)
+	}
+	if got := api.TypeDefs.Get("blob").Kind; got != blob {
+		t.Errorf("foo:b/api#blob refers to %p, expected %p", got, blob)
+	}
+	for _, td := range res.TypeDefs {
+		if td.Owner == bTypes {
+			t.Errorf("type %s owned by duplicate interface", td.TypeName())
+		}
+		if o, ok := td.Kind.(*Option); ok && o.Type.(*TypeDef).Root() != point {
+			t.Errorf("option<point> refers to duplicate point")
+		}
+	}
+
+	// Merging the same package with different definitions must fail
+	c := parse(`package foo:c;
+
+interface api {
+	use shared:types/types@1.0.0.{point};
+}
+
+package shared:types@1.0.0 {
+	interface types {
+		record point { x: u64, y: u64 }
+	}
+}
+`)
+	if _, err := Merge(parse(shared[1:]+"\n"), c); err == nil {
+		t.Errorf("Merge(): expected error for conflicting package definitions")
+	}
+}
+
+func TestMergeDuplicate(t *testing.T) {
+	load := func() *Resolve {
+		t.Helper()
+		res, err := LoadJSON(filepath.Join(testdataPath, "wasi/cli.wit.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	want := load()
+	res, err := Merge(load(), load())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Packages) != len(want.Packages) || len(res.Worlds) != len(want.Worlds) ||
+		len(res.Interfaces) != len(want.Interfaces) || len(res.TypeDefs) != len(want.TypeDefs) {
+		t.Errorf("Merge(): %d packages, %d worlds, %d interfaces, %d types; expected %d, %d, %d, %d",
+			len(res.Packages), len(res.Worlds), len(res.Interfaces), len(res.TypeDefs),
+			len(want.Packages), len(want.Worlds), len(want.Interfaces), len(want.TypeDefs))
+	}
+	if got, want := res.WIT(nil, ""), want.WIT(nil, ""); got != want {
+		t.Errorf("Merge(): WIT did not match")
+	}
+}
@@ -0,0 +1,218 @@
+package wit
+
+import (
+	"slices"
+	"strings"
+
+	"go.bytecodealliance.org/wit/ordered"
+)
+
+// Normalize sorts the contents of [Resolve] r into a canonical order, so that two
+// Resolves with the same definitions produce identical output from [Resolve.WIT]
+// regardless of the order of the input.
+//
+// Packages are sorted by name, with dependencies first. Interfaces, worlds,
+// types, functions, and world imports and exports within each package are sorted by name.
+// The Interfaces, Worlds, and TypeDefs slices of r are then rebuilt in package order,
+// with each interface following the interfaces it uses, and each type following
+// the types it refers to. Any items not reachable from a package keep their relative order
+// at the end of each slice.
+func (r *Resolve) Normalize() {
+	for _, p := range r.Packages {
+		sortMap(&p.Interfaces)
+		sortMap(&p.Worlds)
+	}
+	for _, i := range r.Interfaces {
+		sortMap(&i.TypeDefs)
+		sortMap(&i.Functions)
+	}
+	for _, w := range r.Worlds {
+		sortMap(&w.Imports)
+		sortMap(&w.Exports)
+	}
+
+	n := &normalizer{
+		interfaces: make(map[*Interface]bool),
+		types:      make(map[*TypeDef]bool),
+	}
+	r.Packages = sortPackagesByName(r.Packages)
+	var worlds []*World
+	for _, p := range r.Packages {
+		p.Interfaces.All()(func(_ string, i *Interface) bool {
+			n.visitInterface(i)
+			return true
+		})
+		p.Worlds.All()(func(_ string, w *World) bool {
+			worlds = append(worlds, w)
+			return true
+		})
+	}
+	for _, w := range worlds {
+		w.AllInterfaces()(func(_ string, i *Interface) bool {
+			n.visitInterface(i)
+			return true
+		})
+	}
+	n.sortedInterfaces = appendUnvisited(n.sortedInterfaces, r.Interfaces, n.interfaces)
+
+	for _, i := range n.sortedInterfaces {
+		i.TypeDefs.All()(func(_ string, t *TypeDef) bool {
+			n.visitType(t)
+			return true
+		})
+		i.Functions.All()(func(_ string, f *Function) bool {
+			n.visitFunction(f)
+			return true
+		})
+	}
+	for _, w := range worlds {
+		w.AllItems()(func(_ string, item WorldItem) bool {
+			switch item := item.(type) {
+			case *TypeDef:
+				n.visitType(item)
+			case *Function:
+				n.visitFunction(item)
+			}
+			return true
+		})
+	}
+
+	visited := make(map[*World]bool, len(worlds))
+	for _, w := range worlds {
+		visited[w] = true
+	}
+	r.Interfaces = n.sortedInterfaces
+	r.Worlds = appendUnvisited(worlds, r.Worlds, visited)
+	r.TypeDefs = appendUnvisited(n.sortedTypes, r.TypeDefs, n.types)
+}
+
+type normalizer struct {
+	interfaces       map[*Interface]bool
+	sortedInterfaces []*Interface
+	types            map[*TypeDef]bool
+	sortedTypes      []*TypeDef
+}
+
+// visitInterface appends [Interface] i after the interfaces it uses.
+func (n *normalizer) visitInterface(i *Interface) {
+	if n.interfaces[i] {
+		return
+	}
+	n.interfaces[i] = true
+	for _, dep := range i.uses() {
+		n.visitInterface(dep)
+	}
+	n.sortedInterfaces = append(n.sortedInterfaces, i)
+}
+
+func (n *normalizer) visitFunction(f *Function) {
+	for _, p := range f.Params {
+		n.visitType(p.Type)
+	}
+	for _, p := range f.Results {
+		n.visitType(p.Type)
+	}
+}
+
+// visitType appends t, if it is a [TypeDef], after the types it refers to.
+func (n *normalizer) visitType(t Type) {
+	td, ok := t.(*TypeDef)
+	if !ok || td == nil || n.types[td] {
+		return
+	}
+	n.types[td] = true
+	for _, dep := range kindTypes(td.Kind) {
+		n.visitType(dep)
+	}
+	n.sortedTypes = append(n.sortedTypes, td)
+}
+
+// kindTypes returns the types directly referred to by [TypeDefKind] k.
+// The returned slice may contain nil values.
+func kindTypes(k TypeDefKind) []Type {
+	switch k := k.(type) {
+	case *TypeDef:
+		return []Type{k}
+	case *Record:
+		types := make([]Type, len(k.Fields))
+		for i := range k.Fields {
+			types[i] = k.Fields[i].Type
+		}
+		return types
+	case *Variant:
+		types := make([]Type, len(k.Cases))
+		for i := range k.Cases {
+			types[i] = k.Cases[i].Type
+		}
+		return types
+	case *Tuple:
+		return k.Types
+	case *Option:
+		return []Type{k.Type}
+	case *List:
+		return []Type{k.Type}
+	case *Result:
+		return []Type{k.OK, k.Err}
+	case *Own:
+		return []Type{k.Type}
+	case *Borrow:
+		return []Type{k.Type}
+	case *Future:
+		return []Type{k.Type}
+	case *Stream:
+		return []Type{k.Element, k.End}
+	}
+	return nil
+}
+
+// sortPackagesByName returns packages sorted by name, with dependencies first.
+func sortPackagesByName(packages []*Package) []*Package {
+	byName := slices.Clone(packages)
+	slices.SortStableFunc(byName, func(a, b *Package) int {
+		return strings.Compare(a.Name.String(), b.Name.String())
+	})
+	sorted := make([]*Package, 0, len(packages))
+	visited := make(map[*Package]bool, len(packages))
+	var visit func(p *Package)
+	visit = func(p *Package) {
+		if visited[p] {
+			return
+		}
+		visited[p] = true
+		for _, dep := range byName {
+			if dep != p && DependsOn(p, dep) {
+				visit(dep)
+			}
+		}
+		sorted = append(sorted, p)
+	}
+	for _, p := range byName {
+		visit(p)
+	}
+	return sorted
+}
+
+// sortMap sorts the entries in m by key.
+func sortMap[V any](m *ordered.Map[string, V]) {
+	var keys []string
+	m.All()(func(k string, _ V) bool {
+		keys = append(keys, k)
+		return true
+	})
+	slices.Sort(keys)
+	var sorted ordered.Map[string, V]
+	for _, k := range keys {
+		sorted.Set(k, m.Get(k))
+	}
+	*m = sorted
+}
+
+// appendUnvisited appends each value in s not in visited to sorted, in order.
+func appendUnvisited[T comparable](sorted, s []T, visited map[T]bool) []T {
+	for _, v := range s {
+		if !visited[v] {
+			sorted = append(sorted, v)
+		}
+	}
+	return sorted
+}
@@ -0,0 +1,132 @@
+package wit
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNormalize(t *testing.T) {
+	a := `package foo:root;
+
+interface z {
+	use foo:dep/types.{t};
+	record r { a: list<t>, b: option<string> }
+	run: func(r: r) -> result<t>;
+}
+
+interface a {
+	use z.{r};
+	get: func() -> r;
+	set: func(r: r);
+}
+
+world w {
+	import a;
+	export z;
+	import f: func();
+}
+
+package foo:dep {
+	interface types {
+		type t = u32;
+		enum e { x, y }
+	}
+}
+`
+	b := `package foo:root;
+
+world w {
+	import f: func();
+	export z;
+	import a;
+}
+
+interface a {
+	use z.{r};
+	set: func(r: r);
+	get: func() -> r;
+}
+
+interface z {
+	use foo:dep/types.{t};
+	run: func(r: r) -> result<t>;
+	record r { a: list<t>, b: option<string> }
+}
+
+package foo:dep {
+	interface types {
+		enum e { x, y }
+		type t = u32;
+	}
+}
+`
+	ra, err := ParseWIT(strings.NewReader(a))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rb, err := ParseWIT(strings.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ra.Normalize()
+	rb.Normalize()
+
+	wa, wb := ra.WIT(nil, ""), rb.WIT(nil, "")
+	if wa != wb {
+		t.Errorf("WIT after Normalize differs:\n%s\n---\n%s", wa, wb)
+	}
+
+	names := func(r *Resolve) []string {
+		var s []string
+		for _, p := range r.Packages {
+			s = append(s, p.Name.String())
+		}
+		for _, i := range r.Interfaces {
+			s = append(s, interfaceName(i))
+		}
+		for _, t := range r.TypeDefs {
+			s = append(s, t.QualifiedName()+":"+t.Kind.WITKind())
+		}
+		return s
+	}
+	na, nb := names(ra), names(rb)
+	if strings.Join(na, " ") != strings.Join(nb, " ") {
+		t.Errorf("order after Normalize differs:\n%v\n%v", na, nb)
+	}
+
+	// Dependencies precede their dependents
+	if got, want := ra.Packages[0].Name.String(), "foo:dep"; got != want {
+		t.Errorf("Packages[0]: %s, expected %s", got, want)
+	}
+	seen := make(map[*TypeDef]bool)
+	for _, td := range ra.TypeDefs {
+		for _, dep := range kindTypes(td.Kind) {
+			if dep, ok := dep.(*TypeDef); ok && !seen[dep] {
+				t.Errorf("type %s precedes its dependency %s", td.TypeName(), dep.TypeName())
+			}
+		}
+		seen[td] = true
+	}
+}
+
+func TestNormalizeTestdata(t *testing.T) {
+	err := loadTestdata(func(path string, res *Resolve) error {
+		t.Run(path, func(t *testing.T) {
+			counts := []int{len(res.Packages), len(res.Interfaces), len(res.Worlds), len(res.TypeDefs)}
+			res.Normalize()
+			want := res.WIT(nil, "")
+			if got := []int{len(res.Packages), len(res.Interfaces), len(res.Worlds), len(res.TypeDefs)}; !slices.Equal(got, counts) {
+				t.Errorf("Normalize changed the number of items: %v, expected %v", got, counts)
+			}
+			res.Normalize()
+			if got := res.WIT(nil, ""); got != want {
+				t.Errorf("Normalize is not idempotent:\n%s\n---\n%s", got, want)
+			}
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -16,8 +16,8 @@ import (
 // [WIT package]: https://component-model.bytecodealliance.org/design/wit.html#packages
 type Package struct {
 	Name       Ident
-	Interfaces ordered.Map[string, *Interface]
-	Worlds     ordered.Map[string, *World]
+	Interfaces ordered.Map[string, *Interface] // in declaration order
+	Worlds     ordered.Map[string, *World]     // in declaration order
 	Docs       Docs
 }
 
@@ -29,6 +29,26 @@ func (p *Package) Clone() *Package {
 	return &c
 }
 
+// DefaultWorld returns the only [World] in [Package] p and true.
+// If p has no worlds or more than one world, it returns nil and false.
+func (p *Package) DefaultWorld() (*World, bool) {
+	if p.Worlds.Len() != 1 {
+		return nil, false
+	}
+	var world *World
+	p.Worlds.All()(func(_ string, w *World) bool {
+		world = w
+		return false
+	})
+	return world, true
+}
+
+// HasWorlds returns true if [Package] p defines at least one [World].
+// A package may define only interfaces.
+func (p *Package) HasWorlds() bool {
+	return p.Worlds.Len() > 0
+}
+
 func (p *Package) dependsOn(dep Node) bool {
 	if dep == p {
 		return true
@@ -0,0 +1,114 @@
+package wit
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestPackageDefaultWorld(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:root;
+
+world w {}
+
+package foo:none {
+	interface i {}
+}
+
+package foo:many {
+	world a {}
+	world b {}
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		pkg  string
+		want string
+	}{
+		{"foo:root", "w"},
+		{"foo:none", ""},
+		{"foo:many", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pkg, func(t *testing.T) {
+			id, err := ParseIdent(tt.pkg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p, ok := res.Package(id)
+			if !ok {
+				t.Fatalf("package %s not found", tt.pkg)
+			}
+			w, ok := p.DefaultWorld()
+			var got string
+			if w != nil {
+				got = w.Name
+			}
+			if got != tt.want || ok != (tt.want != "") {
+				t.Errorf("DefaultWorld(): %q, %t, expected %q", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestPackageHasWorlds(t *testing.T) {
+	// An interface-only package, as emitted by wasm-tools, with no "worlds" key.
+	const data = `{
+	"worlds": [],
+	"interfaces": [
+		{
+			"name": "i",
+			"types": {"t": 0},
+			"functions": {},
+			"package": 0
+		}
+	],
+	"types": [
+		{
+			"name": "t",
+			"kind": {"type": "u32"},
+			"owner": {"interface": 0}
+		}
+	],
+	"packages": [
+		{
+			"name": "foo:bar",
+			"interfaces": {"i": 0}
+		}
+	]
+}`
+	res, err := DecodeJSON(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := res.Packages[0]
+	if p.HasWorlds() {
+		t.Errorf("HasWorlds(): true, expected false")
+	}
+	if w, ok := p.DefaultWorld(); w != nil || ok {
+		t.Errorf("DefaultWorld(): %v, %t, expected nil, false", w, ok)
+	}
+	p.Worlds.All()(func(name string, _ *World) bool {
+		t.Errorf("unexpected world %s", name)
+		return true
+	})
+
+	// These must not panic on a Resolve without worlds.
+	_ = res.WIT(nil, "")
+	_ = res.Stats()
+	_ = res.Search("i")
+	if err := res.DOT(io.Discard); err != nil {
+		t.Error(err)
+	}
+	res.Normalize()
+
+	res, err = ParseWIT(strings.NewReader("package foo:bar;\n\nworld w {}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Packages[0].HasWorlds() {
+		t.Errorf("HasWorlds(): false, expected true")
+	}
+}
@@ -0,0 +1,1861 @@
+package wit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/coreos/go-semver/semver"
+	"go.bytecodealliance.org/wit/ordered"
+)
+
+// ParseWIT parses [WIT] text from Reader r into a [Resolve], without requiring [wasm-tools].
+//
+// ParseWIT supports a subset of the WIT grammar: one or more packages containing interfaces
+// and worlds, use statements, type definitions, resources, functions, and feature gates
+// (@since, @unstable, and @deprecated), and world include statements. References to an interface
+// or world in another package must resolve to a package declared in the same input, e.g. using
+// nested package syntax. Unsupported syntax, such as top-level use statements,
+// results in an error that wraps [errors.ErrUnsupported].
+//
+// Use [LoadWIT] or [DecodeWIT] for full fidelity with the wasm-tools WIT parser.
+//
+// [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
+// [wasm-tools]: https://crates.io/crates/wasm-tools
+func ParseWIT(r io.Reader) (*Resolve, error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{src: string(src), line: 1}
+	p.next()
+	pkgs := p.parseFile()
+	if p.err != nil {
+		return nil, p.err
+	}
+	return resolveAST(pkgs)
+}
+
+// AST types produced by the parser and consumed by the resolver.
+type (
+	astPackage struct {
+		name       Ident
+		docs       Docs
+		interfaces []*astInterface
+		worlds     []*astWorld
+	}
+
+	astInterface struct {
+		name  string // empty if inline
+		docs  Docs
+		gate  Stability
+		items []any // *astUse, *astTypeDef, or *astFunc
+	}
+
+	astWorld struct {
+		name  string
+		docs  Docs
+		gate  Stability
+		items []any // *astUse, *astInclude, *astTypeDef, or *astWorldItem
+	}
+
+	// astInclude is an include statement in a world, e.g. include wasi:cli/imports@0.2.0 with { a as b };
+	astInclude struct {
+		path astPath
+		with []astUseName
+		line int
+	}
+
+	// astWorldItem is an import or export in a world, which is one of
+	// an interface reference (path), an inline interface, or a function.
+	astWorldItem struct {
+		export bool
+		name   string
+		path   *astPath
+		iface  *astInterface
+		fn     *astFunc
+		gate   Stability
+		line   int
+	}
+
+	astUse struct {
+		path  astPath
+		names []astUseName
+		gate  Stability
+		line  int
+	}
+
+	astUseName struct {
+		name string
+		as   string
+	}
+
+	// astPath is a reference to an interface, either local ("name")
+	// or in another package ("namespace:package/name@version").
+	astPath struct {
+		pkg  *Ident
+		name string
+		line int
+	}
+
+	astTypeDef struct {
+		name   string
+		kind   string // type, record, variant, enum, flags, or resource
+		typ    *astType
+		fields []astField // record fields, variant cases, enum cases, or flags
+		funcs  []*astFunc // resource functions
+		docs   Docs
+		gate   Stability
+		line   int
+	}
+
+	astField struct {
+		name string
+		typ  *astType // nil for enum cases, flags, and variant cases without a payload
+		docs Docs
+	}
+
+	astFunc struct {
+		name    string
+		kind    string // func, static, method, or constructor
+		params  []astField
+		results []astField // a single anonymous result has an empty name
+		docs    Docs
+		gate    Stability
+		line    int
+	}
+
+	astType struct {
+		name     string // "_" for an omitted result type
+		explicit bool   // %-prefixed identifier
+		params   []*astType
+		line     int
+	}
+)
+
+type tokenKind int
+
+const (
+	tokenEOF      tokenKind = iota
+	tokenIdent              // an identifier or keyword
+	tokenExplicit           // a %-prefixed identifier, which is never a keyword
+	tokenPunct              // punctuation, e.g. "{" or "->"
+)
+
+// parser is a recursive descent parser for WIT text.
+// The first error encountered is stored in err, after which the parser stops advancing.
+type parser struct {
+	src  string
+	pos  int
+	line int
+
+	kind    tokenKind
+	tok     string
+	tokLine int
+	docs    []string // doc comments preceding the current token
+	err     error
+}
+
+func (p *parser) errorf(format string, args ...any) {
+	if p.err == nil {
+		p.err = fmt.Errorf("line %d: "+format, append([]any{p.tokLine}, args...)...)
+	}
+	p.kind, p.tok = tokenEOF, ""
+}
+
+func (p *parser) unsupported(what string) {
+	if p.err == nil {
+		p.err = fmt.Errorf("line %d: %s: %w", p.tokLine, what, errors.ErrUnsupported)
+	}
+	p.kind, p.tok = tokenEOF, ""
+}
+
+// next advances to the next token.
+func (p *parser) next() {
+	p.docs = nil
+	p.skip()
+	p.tokLine = p.line
+	if p.err != nil || p.pos >= len(p.src) {
+		p.kind, p.tok = tokenEOF, ""
+		return
+	}
+	switch c := p.src[p.pos]; {
+	case c == '%' || isLetter(c):
+		start := p.pos
+		p.pos++
+		for p.pos < len(p.src) && (isLetter(p.src[p.pos]) || isDigit(p.src[p.pos]) || p.src[p.pos] == '-') {
+			p.pos++
+		}
+		p.kind, p.tok = tokenIdent, p.src[start:p.pos]
+		if c == '%' {
+			p.kind, p.tok = tokenExplicit, p.tok[1:]
+			if p.tok == "" {
+				p.errorf("expected identifier after %%")
+			}
+		}
+	case strings.HasPrefix(p.src[p.pos:], "->"):
+		p.kind, p.tok = tokenPunct, "->"
+		p.pos += 2
+	case strings.IndexByte("{}()<>,;:=./@*_", c) >= 0:
+		p.kind, p.tok = tokenPunct, p.src[p.pos:p.pos+1]
+		p.pos++
+	default:
+		p.errorf("unexpected character %q", c)
+	}
+}
+
+// skip skips whitespace and comments, collecting doc comments.
+func (p *parser) skip() {
+	for p.pos < len(p.src) {
+		rest := p.src[p.pos:]
+		switch {
+		case rest[0] == '\n':
+			p.line++
+			p.pos++
+		case rest[0] == ' ' || rest[0] == '\t' || rest[0] == '\r':
+			p.pos++
+		case strings.HasPrefix(rest, "//"):
+			end := strings.IndexByte(rest, '\n')
+			if end < 0 {
+				end = len(rest)
+			}
+			if strings.HasPrefix(rest, "///") && !strings.HasPrefix(rest, "////") {
+				line := strings.TrimPrefix(rest[3:end], " ")
+				p.docs = append(p.docs, strings.TrimRight(line, " \t\r"))
+			}
+			p.pos += end
+		case strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest, "*/")
+			if end < 0 {
+				p.errorf("unterminated block comment")
+				p.pos = len(p.src)
+				return
+			}
+			p.line += strings.Count(rest[:end], "\n")
+			p.pos += end + 2
+		default:
+			return
+		}
+	}
+}
+
+// peek returns the token following the current token.
+func (p *parser) peek() string {
+	q := *p
+	q.next()
+	return q.tok
+}
+
+// is reports whether the current token is keyword or punctuation s.
+func (p *parser) is(s string) bool {
+	return p.kind != tokenExplicit && p.tok == s
+}
+
+// accept advances and returns true if the current token is s.
+func (p *parser) accept(s string) bool {
+	if p.is(s) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(s string) {
+	if !p.accept(s) {
+		p.errorf("expected %q, found %q", s, p.tok)
+	}
+}
+
+// takeDocs returns the doc comments preceding the current token.
+func (p *parser) takeDocs() Docs {
+	docs := Docs{Contents: strings.Join(p.docs, "\n")}
+	p.docs = nil
+	return docs
+}
+
+func (p *parser) ident() string {
+	if p.kind != tokenIdent && p.kind != tokenExplicit {
+		p.errorf("expected identifier, found %q", p.tok)
+		return ""
+	}
+	s := p.tok
+	p.next()
+	return s
+}
+
+// version parses a semantic version following the current "@" or "=" token.
+func (p *parser) version() *semver.Version {
+	if p.err != nil {
+		return nil
+	}
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+	start := p.pos
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if !isLetter(c) && !isDigit(c) && c != '.' && c != '-' && c != '+' {
+			break
+		}
+		p.pos++
+	}
+	// A trailing "." is punctuation, e.g. in "use a:b/c@1.0.0.{d}"
+	if p.pos > start && p.src[p.pos-1] == '.' {
+		p.pos--
+	}
+	v, err := semver.NewVersion(p.src[start:p.pos])
+	if err != nil {
+		p.errorf("invalid version %q: %v", p.src[start:p.pos], err)
+		return nil
+	}
+	p.next()
+	return v
+}
+
+// parseFile parses a WIT file containing one or more packages.
+func (p *parser) parseFile() []*astPackage {
+	var pkgs []*astPackage
+	var root *astPackage
+	for p.err == nil && p.kind != tokenEOF {
+		docs := p.takeDocs()
+		if p.accept("package") {
+			pkg := &astPackage{name: p.parsePackageName(), docs: docs}
+			pkgs = append(pkgs, pkg)
+			if p.accept("{") {
+				for p.err == nil && !p.accept("}") {
+					p.parsePackageItem(pkg, p.takeDocs())
+				}
+				continue
+			}
+			p.expect(";")
+			if root != nil {
+				p.errorf("duplicate package declaration %s", pkg.name.String())
+			}
+			root = pkg
+			pkgs = pkgs[:len(pkgs)-1]
+			continue
+		}
+		if root == nil {
+			p.errorf("expected package declaration, found %q", p.tok)
+			break
+		}
+		p.parsePackageItem(root, docs)
+	}
+	if root != nil {
+		// The root package is last, following any nested packages
+		pkgs = append(pkgs, root)
+	}
+	if p.err == nil && len(pkgs) == 0 {
+		p.errorf("expected package declaration")
+	}
+	return pkgs
+}
+
+// parsePackageName parses a package name, e.g. "wasi:io@0.2.0".
+func (p *parser) parsePackageName() Ident {
+	var id Ident
+	id.Namespace = p.ident()
+	p.expect(":")
+	id.Package = p.ident()
+	if p.is(":") || p.is("/") {
+		p.unsupported("nested namespace or interface in package name")
+	}
+	if p.is("@") {
+		id.Version = p.version()
+	}
+	return id
+}
+
+func (p *parser) parsePackageItem(pkg *astPackage, docs Docs) {
+	gate := p.parseGates()
+	switch {
+	case p.accept("interface"):
+		i := &astInterface{name: p.ident(), docs: docs, gate: gate}
+		p.parseInterfaceItems(i)
+		pkg.interfaces = append(pkg.interfaces, i)
+	case p.accept("world"):
+		w := &astWorld{name: p.ident(), docs: docs, gate: gate}
+		p.parseWorldItems(w)
+		pkg.worlds = append(pkg.worlds, w)
+	case p.is("use"):
+		p.unsupported("top-level use")
+	default:
+		p.errorf("expected interface or world, found %q", p.tok)
+	}
+}
+
+// parseGates parses zero or more feature gates, e.g. @since(version = 0.2.0).
+func (p *parser) parseGates() Stability {
+	var s Stability
+	var deprecated *semver.Version
+	for p.err == nil && p.accept("@") {
+		switch gate := p.ident(); gate {
+		case "since":
+			p.expect("(")
+			if p.ident() != "version" {
+				p.errorf("expected version")
+			}
+			if !p.is("=") {
+				p.errorf("expected \"=\", found %q", p.tok)
+			}
+			stable := &Stable{}
+			if v := p.version(); v != nil {
+				stable.Since = *v
+			}
+			if p.accept(",") {
+				// Older syntax: @since(version = x, feature = y)
+				if p.ident() != "feature" {
+					p.errorf("expected feature")
+				}
+				p.expect("=")
+				p.ident()
+			}
+			p.expect(")")
+			s = stable
+		case "unstable":
+			p.expect("(")
+			if p.ident() != "feature" {
+				p.errorf("expected feature")
+			}
+			p.expect("=")
+			s = &Unstable{Feature: p.ident()}
+			p.expect(")")
+		case "deprecated":
+			p.expect("(")
+			if p.ident() != "version" {
+				p.errorf("expected version")
+			}
+			if !p.is("=") {
+				p.errorf("expected \"=\", found %q", p.tok)
+			}
+			deprecated = p.version()
+			p.expect(")")
+		default:
+			p.errorf("unknown feature gate @%s", gate)
+		}
+	}
+	switch s := s.(type) {
+	case *Stable:
+		s.Deprecated = deprecated
+	case *Unstable:
+		s.Deprecated = deprecated
+	}
+	return s
+}
+
+func (p *parser) parseInterfaceItems(i *astInterface) {
+	p.expect("{")
+	for p.err == nil && !p.accept("}") {
+		docs := p.takeDocs()
+		gate := p.parseGates()
+		switch {
+		case p.is("use"):
+			u := p.parseUse()
+			u.gate = gate
+			i.items = append(i.items, u)
+		case p.isTypeDef():
+			t := p.parseTypeDef()
+			t.docs, t.gate = docs, gate
+			i.items = append(i.items, t)
+		default:
+			line := p.tokLine
+			name := p.ident()
+			p.expect(":")
+			f := p.parseFunc(name, false)
+			f.docs, f.gate, f.line = docs, gate, line
+			i.items = append(i.items, f)
+		}
+	}
+}
+
+func (p *parser) parseWorldItems(w *astWorld) {
+	p.expect("{")
+	for p.err == nil && !p.accept("}") {
+		docs := p.takeDocs()
+		gate := p.parseGates()
+		switch {
+		case p.is("import") || p.is("export"):
+			item := &astWorldItem{export: p.is("export"), gate: gate, line: p.tokLine}
+			p.next()
+			if (p.kind == tokenIdent || p.kind == tokenExplicit) && p.peek() == ":" {
+				name := p.ident()
+				p.expect(":")
+				switch {
+				case p.is("interface"):
+					p.next()
+					item.name = name
+					item.iface = &astInterface{docs: docs, gate: gate}
+					p.parseInterfaceItems(item.iface)
+				case p.is("func") || p.is("async"):
+					item.name = name
+					item.fn = p.parseFunc(name, false)
+					item.fn.docs, item.fn.gate, item.fn.line = docs, gate, item.line
+				default:
+					// Fully-qualified interface reference, e.g. wasi:io/streams@0.2.0
+					path := p.parsePathAfterNamespace(name)
+					item.path = &path
+					p.expect(";")
+				}
+			} else {
+				path := p.parsePath()
+				item.path = &path
+				p.expect(";")
+			}
+			w.items = append(w.items, item)
+		case p.is("use"):
+			u := p.parseUse()
+			u.gate = gate
+			w.items = append(w.items, u)
+		case p.is("include"):
+			w.items = append(w.items, p.parseInclude())
+		case p.isTypeDef():
+			t := p.parseTypeDef()
+			t.docs, t.gate = docs, gate
+			w.items = append(w.items, t)
+		default:
+			p.errorf("expected import, export, use, or type definition, found %q", p.tok)
+		}
+	}
+}
+
+// isTypeDef reports whether the current token starts a type definition,
+// as opposed to a function with the same name as a keyword, e.g. "record: func()".
+func (p *parser) isTypeDef() bool {
+	switch {
+	case p.kind != tokenIdent:
+		return false
+	case p.tok == "type", p.tok == "record", p.tok == "variant", p.tok == "enum", p.tok == "flags", p.tok == "resource":
+		return p.peek() != ":"
+	}
+	return false
+}
+
+// parsePath parses a reference to an interface, e.g. "streams" or "wasi:io/streams@0.2.0".
+func (p *parser) parsePath() astPath {
+	name := p.ident()
+	if !p.accept(":") {
+		return astPath{name: name, line: p.tokLine}
+	}
+	return p.parsePathAfterNamespace(name)
+}
+
+func (p *parser) parsePathAfterNamespace(namespace string) astPath {
+	path := astPath{pkg: &Ident{Namespace: namespace}, line: p.tokLine}
+	path.pkg.Package = p.ident()
+	p.expect("/")
+	path.name = p.ident()
+	if p.is("@") {
+		path.pkg.Version = p.version()
+	}
+	return path
+}
+
+// parseInclude parses an include statement, e.g. include wasi:cli/imports@0.2.0 with { a as b };
+func (p *parser) parseInclude() *astInclude {
+	inc := &astInclude{line: p.tokLine}
+	p.expect("include")
+	inc.path = p.parsePath()
+	if p.accept("with") {
+		p.expect("{")
+		for p.err == nil && !p.accept("}") {
+			n := astUseName{name: p.ident()}
+			p.expect("as")
+			n.as = p.ident()
+			inc.with = append(inc.with, n)
+			if !p.accept(",") {
+				p.expect("}")
+				break
+			}
+		}
+	}
+	p.expect(";")
+	return inc
+}
+
+// parseUse parses a use statement, e.g. use wasi:io/streams@0.2.0.{input-stream, output-stream as out};
+func (p *parser) parseUse() *astUse {
+	u := &astUse{line: p.tokLine}
+	p.expect("use")
+	u.path = p.parsePath()
+	if !p.is(".") {
+		p.unsupported("use without names")
+		return u
+	}
+	p.expect(".")
+	p.expect("{")
+	for p.err == nil && !p.accept("}") {
+		n := astUseName{name: p.ident()}
+		if p.accept("as") {
+			n.as = p.ident()
+		}
+		u.names = append(u.names, n)
+		if !p.accept(",") {
+			p.expect("}")
+			break
+		}
+	}
+	p.expect(";")
+	return u
+}
+
+func (p *parser) parseTypeDef() *astTypeDef {
+	t := &astTypeDef{kind: p.tok, line: p.tokLine}
+	p.next()
+	t.name = p.ident()
+	switch t.kind {
+	case "type":
+		p.expect("=")
+		t.typ = p.parseType()
+		p.expect(";")
+	case "resource":
+		if p.accept(";") {
+			break
+		}
+		p.expect("{")
+		for p.err == nil && !p.accept("}") {
+			docs := p.takeDocs()
+			gate := p.parseGates()
+			line := p.tokLine
+			var f *astFunc
+			if p.accept("constructor") {
+				f = &astFunc{kind: "constructor"}
+				f.params = p.parseParams()
+				if p.is("->") {
+					p.unsupported("fallible constructor")
+				}
+				p.expect(";")
+			} else {
+				name := p.ident()
+				p.expect(":")
+				f = p.parseFunc(name, true)
+			}
+			f.docs, f.gate, f.line = docs, gate, line
+			t.funcs = append(t.funcs, f)
+		}
+	default:
+		p.expect("{")
+		for p.err == nil && !p.accept("}") {
+			f := astField{docs: p.takeDocs(), name: p.ident()}
+			switch {
+			case t.kind == "record":
+				p.expect(":")
+				f.typ = p.parseType()
+			case t.kind == "variant" && p.accept("("):
+				f.typ = p.parseType()
+				p.expect(")")
+			}
+			t.fields = append(t.fields, f)
+			if !p.accept(",") {
+				p.expect("}")
+				break
+			}
+		}
+		if (t.kind == "variant" || t.kind == "enum") && len(t.fields) == 0 {
+			p.errorf("empty %s %s", t.kind, t.name)
+		}
+	}
+	return t
+}
+
+// parseFunc parses a function type following "name:", e.g. func(a: u32) -> string;
+// Functions in a resource are methods unless marked static.
+func (p *parser) parseFunc(name string, resource bool) *astFunc {
+	f := &astFunc{name: name, kind: "func"}
+	if resource {
+		f.kind = "method"
+		if p.accept("static") {
+			f.kind = "static"
+		}
+	}
+	if p.is("async") {
+		p.unsupported("async function")
+		return f
+	}
+	p.expect("func")
+	f.params = p.parseParams()
+	if p.accept("->") {
+		if p.is("(") {
+			f.results = p.parseParams()
+		} else {
+			f.results = []astField{{typ: p.parseType()}}
+		}
+	}
+	p.expect(";")
+	return f
+}
+
+func (p *parser) parseParams() []astField {
+	var params []astField
+	p.expect("(")
+	for p.err == nil && !p.accept(")") {
+		f := astField{name: p.ident()}
+		if slices.ContainsFunc(params, func(param astField) bool { return param.name == f.name }) {
+			p.errorf("duplicate name %s", f.name)
+		}
+		p.expect(":")
+		f.typ = p.parseType()
+		params = append(params, f)
+		if !p.accept(",") {
+			p.expect(")")
+			break
+		}
+	}
+	return params
+}
+
+// parseType parses a type, e.g. u32, list<u8>, or result<_, error-code>.
+func (p *parser) parseType() *astType {
+	t := &astType{explicit: p.kind == tokenExplicit, line: p.tokLine}
+	t.name = p.ident()
+	if t.explicit || !p.is("<") {
+		return t
+	}
+	switch t.name {
+	case "list", "option", "result", "tuple", "borrow", "own", "future", "stream":
+	default:
+		return t
+	}
+	p.expect("<")
+	for p.err == nil && !p.accept(">") {
+		if (t.name == "result" || t.name == "stream") && p.accept("_") {
+			t.params = append(t.params, &astType{name: "_", line: p.tokLine})
+		} else {
+			t.params = append(t.params, p.parseType())
+		}
+		if !p.accept(",") {
+			p.expect(">")
+			break
+		}
+	}
+	// The _ placeholder is only valid for the ok type of a result with an error type
+	if n := len(t.params); t.name == "result" && n > 0 && t.params[n-1].name == "_" && !t.params[n-1].explicit {
+		p.errorf("expected error type following _ in result")
+	}
+	return t
+}
+
+func isLetter(c byte) bool { return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' }
+func isDigit(c byte) bool  { return c >= '0' && c <= '9' }
+
+// resolver resolves parsed WIT packages into a [Resolve].
+type resolver struct {
+	res      *Resolve
+	pending  map[*TypeDef]pendingTypeDef
+	declared []*TypeDef        // named types in declaration order
+	defined  map[*TypeDef]bool // true while resolving, false once resolved
+	uses     []pendingUse
+	funcs    []pendingFunc
+	scopes   map[*Interface]scope
+	ifaces   map[*astInterface]*Interface
+	deps     map[*Interface][]*Interface
+	copies   map[*World]*worldCopy // items copied into each world by include
+}
+
+// scope maps type names to their [TypeDef] in an [Interface] or [World].
+type scope map[string]*TypeDef
+
+type pendingTypeDef struct {
+	ast   *astTypeDef
+	scope scope
+}
+
+type pendingUse struct {
+	typ   *TypeDef
+	owner *Interface // nil for a World
+	from  *Interface
+	name  string
+	line  int
+}
+
+type pendingFunc struct {
+	fn       *Function
+	ast      *astFunc
+	resource *TypeDef // for resource functions
+	scope    scope
+}
+
+func resolveAST(pkgs []*astPackage) (*Resolve, error) {
+	r := &resolver{
+		res:     &Resolve{},
+		pending: make(map[*TypeDef]pendingTypeDef),
+		defined: make(map[*TypeDef]bool),
+		scopes:  make(map[*Interface]scope),
+		ifaces:  make(map[*astInterface]*Interface),
+		deps:    make(map[*Interface][]*Interface),
+	}
+
+	// Declare packages, interfaces, and worlds
+	worlds := make(map[*World]*astWorld)
+	for _, ap := range pkgs {
+		pkg := &Package{Name: ap.name, Docs: ap.docs}
+		for _, other := range r.res.Packages {
+			if other.Name.String() == pkg.Name.String() {
+				return nil, fmt.Errorf("duplicate package %s", pkg.Name.String())
+			}
+		}
+		r.res.Packages = append(r.res.Packages, pkg)
+		for _, ai := range ap.interfaces {
+			if _, ok := pkg.Interfaces.GetOK(ai.name); ok {
+				return nil, fmt.Errorf("duplicate interface %s in package %s", ai.name, pkg.Name.String())
+			}
+			name := ai.name
+			i := &Interface{Name: &name, Package: pkg, Stability: ai.gate, Docs: ai.docs}
+			pkg.Interfaces.Set(name, i)
+			r.ifaces[ai] = i
+			r.res.Interfaces = append(r.res.Interfaces, i)
+		}
+		for _, aw := range ap.worlds {
+			if _, ok := pkg.Worlds.GetOK(aw.name); ok {
+				return nil, fmt.Errorf("duplicate world %s in package %s", aw.name, pkg.Name.String())
+			}
+			w := &World{Name: aw.name, Package: pkg, Stability: aw.gate, Docs: aw.docs}
+			pkg.Worlds.Set(aw.name, w)
+			worlds[w] = aw
+			r.res.Worlds = append(r.res.Worlds, w)
+		}
+	}
+
+	// Declare interface contents
+	for _, ap := range pkgs {
+		for _, ai := range ap.interfaces {
+			if err := r.declareInterface(r.ifaces[ai], ai); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Declare world contents
+	for _, w := range r.res.Worlds {
+		if err := r.declareWorld(w, worlds[w]); err != nil {
+			return nil, err
+		}
+	}
+
+	// Resolve use statements, then type definitions, then functions
+	for _, u := range r.uses {
+		t, ok := r.scopes[u.from][u.name]
+		if !ok {
+			return nil, fmt.Errorf("line %d: type %s not defined in interface %s", u.line, u.name, interfaceName(u.from))
+		}
+		u.typ.Kind = t
+		if u.owner != nil && !containsInterface(r.deps[u.owner], u.from) {
+			r.deps[u.owner] = append(r.deps[u.owner], u.from)
+		}
+		r.res.TypeDefs = append(r.res.TypeDefs, u.typ)
+	}
+	for _, u := range r.uses {
+		if r.dependsOn(u.from, u.owner) {
+			return nil, fmt.Errorf("line %d: interface %s depends on itself", u.line, interfaceName(u.from))
+		}
+	}
+	var owners []TypeOwner
+	for _, i := range r.sortInterfaces(r.res.Interfaces) {
+		owners = append(owners, i)
+	}
+	for _, w := range r.res.Worlds {
+		owners = append(owners, w)
+	}
+	for _, owner := range owners {
+		var types []*TypeDef
+		for _, t := range r.declared {
+			if t.Owner == owner {
+				types = append(types, t)
+			}
+		}
+		for _, t := range r.sortTypeDefs(types) {
+			if err := r.define(t); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, pf := range r.funcs {
+		if err := r.resolveFunc(pf); err != nil {
+			return nil, err
+		}
+	}
+
+	// Order the types in each interface topologically, as they were resolved
+	for _, i := range r.res.Interfaces {
+		i.TypeDefs = ordered.Map[string, *TypeDef]{}
+	}
+	for _, t := range r.res.TypeDefs {
+		if i, ok := t.Owner.(*Interface); ok {
+			i.TypeDefs.Set(t.TypeName(), t)
+		}
+	}
+
+	// Include worlds, after the functions and types they contain are resolved
+	included := make(map[*World]bool)
+	for _, w := range r.res.Worlds {
+		if err := r.includeWorlds(w, worlds, included); err != nil {
+			return nil, err
+		}
+	}
+
+	// Import interfaces used by other interfaces in each world
+	for _, w := range r.res.Worlds {
+		r.elaborateWorld(w)
+	}
+
+	// Sort interfaces and packages topologically, dependencies first
+	r.res.Interfaces = r.sortInterfaces(r.res.Interfaces)
+	r.res.Packages = sortPackages(r.res.Packages)
+
+	return r.res, nil
+}
+
+func (r *resolver) declareInterface(i *Interface, ai *astInterface) error {
+	sc := make(scope)
+	r.scopes[i] = sc
+	for _, item := range ai.items {
+		switch item := item.(type) {
+		case *astUse:
+			from, err := r.lookupInterface(i.Package, item.path)
+			if err != nil {
+				return err
+			}
+			for _, n := range item.names {
+				t, err := r.declareUse(sc, i, from, n, item)
+				if err != nil {
+					return err
+				}
+				i.TypeDefs.Set(t.TypeName(), t)
+			}
+		case *astTypeDef:
+			t, err := r.declareTypeDef(sc, i, item)
+			if err != nil {
+				return err
+			}
+			i.TypeDefs.Set(t.TypeName(), t)
+			for _, f := range r.declareResourceFuncs(sc, t, item) {
+				if _, ok := i.Functions.GetOK(f.Name); ok {
+					return fmt.Errorf("line %d: duplicate function %s", item.line, f.Name)
+				}
+				i.Functions.Set(f.Name, f)
+			}
+		case *astFunc:
+			if _, ok := i.Functions.GetOK(item.name); ok {
+				return fmt.Errorf("line %d: duplicate function %s", item.line, item.name)
+			}
+			i.Functions.Set(item.name, r.declareFunc(sc, nil, item))
+		}
+	}
+	return nil
+}
+
+func (r *resolver) declareWorld(w *World, aw *astWorld) error {
+	sc := make(scope)
+	for _, item := range aw.items {
+		switch item := item.(type) {
+		case *astUse:
+			from, err := r.lookupInterface(w.Package, item.path)
+			if err != nil {
+				return err
+			}
+			if !worldHasInterface(w, from) {
+				w.Imports.Set(interfaceName(from), &InterfaceRef{Interface: from})
+			}
+			use := WorldUse{Interface: from}
+			for _, n := range item.names {
+				t, err := r.declareUse(sc, w, from, n, item)
+				if err != nil {
+					return err
+				}
+				if _, ok := w.Imports.GetOK(t.TypeName()); ok {
+					return fmt.Errorf("line %d: duplicate world item %s", item.line, t.TypeName())
+				}
+				w.Imports.Set(t.TypeName(), t)
+				use.Types = append(use.Types, t)
+			}
+			w.Uses = append(w.Uses, use)
+		case *astTypeDef:
+			t, err := r.declareTypeDef(sc, w, item)
+			if err != nil {
+				return err
+			}
+			if _, ok := w.Imports.GetOK(t.TypeName()); ok {
+				return fmt.Errorf("line %d: duplicate world item %s", item.line, t.TypeName())
+			}
+			w.Imports.Set(t.TypeName(), t)
+			for _, f := range r.declareResourceFuncs(sc, t, item) {
+				if _, ok := w.Imports.GetOK(f.Name); ok {
+					return fmt.Errorf("line %d: duplicate world item %s", item.line, f.Name)
+				}
+				w.Imports.Set(f.Name, f)
+			}
+		case *astWorldItem:
+			items := &w.Imports
+			if item.export {
+				items = &w.Exports
+			}
+			var name string
+			var v WorldItem
+			switch {
+			case item.path != nil:
+				i, err := r.lookupInterface(w.Package, *item.path)
+				if err != nil {
+					return err
+				}
+				name = interfaceName(i)
+				v = &InterfaceRef{Interface: i, Stability: item.gate}
+			case item.iface != nil:
+				i := &Interface{Package: w.Package, Docs: item.iface.docs}
+				r.ifaces[item.iface] = i
+				r.res.Interfaces = append(r.res.Interfaces, i)
+				if err := r.declareInterface(i, item.iface); err != nil {
+					return err
+				}
+				name = item.name
+				v = &InterfaceRef{Interface: i, Stability: item.gate}
+			case item.fn != nil:
+				name = item.name
+				v = r.declareFunc(sc, nil, item.fn)
+			}
+			if prev, ok := items.GetOK(name); ok && !sameInterfaceRef(prev, v) {
+				return fmt.Errorf("line %d: duplicate world item %s", item.line, name)
+			}
+			items.Set(name, v)
+		}
+	}
+	return nil
+}
+
+// includeWorlds adds the imports and exports of each world included by [World] w to w,
+// first including worlds into the worlds that w includes. A world may not include itself.
+// Included maps each world to true once its includes are complete, or false while in progress.
+func (r *resolver) includeWorlds(w *World, worlds map[*World]*astWorld, included map[*World]bool) error {
+	if done, ok := included[w]; ok {
+		if !done {
+			return fmt.Errorf("world %s includes itself", worldName(w))
+		}
+		return nil
+	}
+	included[w] = false
+	for _, item := range worlds[w].items {
+		inc, ok := item.(*astInclude)
+		if !ok {
+			continue
+		}
+		from, err := r.lookupWorld(w.Package, inc.path)
+		if err != nil {
+			return err
+		}
+		if err := r.includeWorlds(from, worlds, included); err != nil {
+			return err
+		}
+		include := WorldInclude{World: from}
+		for _, n := range inc.with {
+			if _, ok := from.Imports.GetOK(n.name); !ok {
+				if _, ok := from.Exports.GetOK(n.name); !ok {
+					return fmt.Errorf("line %d: world %s has no import or export %s", inc.line, worldName(from), n.name)
+				}
+			}
+			if include.With == nil {
+				include.With = make(map[string]string)
+			}
+			include.With[n.name] = n.as
+		}
+		for _, items := range [][2]*ordered.Map[string, WorldItem]{
+			{&from.Imports, &w.Imports},
+			{&from.Exports, &w.Exports},
+		} {
+			src, dst := items[0], items[1]
+			var err error
+			src.All()(func(name string, v WorldItem) bool {
+				v = r.worldCopy(w, from).item(v)
+				if as, ok := include.With[name]; ok {
+					switch item := v.(type) {
+					case *Function:
+						f := *item
+						f.Name = as
+						v = &f
+					case *TypeDef:
+						err = fmt.Errorf("line %d: rename of type %s in include: %w", inc.line, name, errors.ErrUnsupported)
+						return false
+					}
+					name = as
+				}
+				if prev, ok := dst.GetOK(name); ok && !sameInterfaceRef(prev, v) && prev != v {
+					err = fmt.Errorf("line %d: duplicate world item %s in include of world %s", inc.line, name, worldName(from))
+					return false
+				}
+				dst.Set(name, v)
+				return true
+			})
+			if err != nil {
+				return err
+			}
+		}
+		w.Includes = append(w.Includes, include)
+	}
+	included[w] = true
+	return nil
+}
+
+// worldCopy returns the [worldCopy] of the items included into [World] w from world from.
+func (r *resolver) worldCopy(w, from *World) *worldCopy {
+	if r.copies == nil {
+		r.copies = make(map[*World]*worldCopy)
+	}
+	c := r.copies[w]
+	if c == nil {
+		c = &worldCopy{
+			res:    r.res,
+			w:      w,
+			types:  make(map[*TypeDef]*TypeDef),
+			funcs:  make(map[*Function]*Function),
+			ifaces: make(map[*Interface]*Interface),
+		}
+		r.copies[w] = c
+	}
+	c.from = from
+	return c
+}
+
+// worldCopy copies the items of an included world into the including world w, as wasm-tools does.
+// Types defined in the included world are copied with Owner w, inline interfaces are copied with
+// their types and functions, and references to copied types are replaced by their copies.
+// Each item is copied once, so a world included more than once contributes the same copies.
+// Named interfaces are not copied.
+type worldCopy struct {
+	res    *Resolve
+	w      *World
+	from   *World
+	types  map[*TypeDef]*TypeDef
+	funcs  map[*Function]*Function
+	ifaces map[*Interface]*Interface
+}
+
+// item returns the copy of world item v.
+func (c *worldCopy) item(v WorldItem) WorldItem {
+	switch v := v.(type) {
+	case *TypeDef:
+		return c.typeDef(v)
+	case *Function:
+		return c.function(v)
+	case *InterfaceRef:
+		if v.Interface.Name != nil {
+			return v
+		}
+		ref := *v
+		ref.Interface = c.iface(v.Interface)
+		return &ref
+	}
+	return v
+}
+
+// copied returns true if t is defined in the included world or one of its inline interfaces.
+func (c *worldCopy) copied(t *TypeDef) bool {
+	switch owner := t.Owner.(type) {
+	case *World:
+		return owner == c.from
+	case *Interface:
+		_, ok := c.ifaces[owner]
+		return ok
+	}
+	return false
+}
+
+func (c *worldCopy) iface(i *Interface) *Interface {
+	if copy, ok := c.ifaces[i]; ok {
+		return copy
+	}
+	copy := &Interface{
+		Package:   i.Package,
+		Stability: i.Stability,
+		Docs:      i.Docs,
+	}
+	c.ifaces[i] = copy
+	c.res.Interfaces = append(c.res.Interfaces, copy)
+	i.TypeDefs.All()(func(name string, t *TypeDef) bool {
+		copy.TypeDefs.Set(name, c.typeDef(t))
+		return true
+	})
+	i.Functions.All()(func(name string, f *Function) bool {
+		copy.Functions.Set(name, c.function(f))
+		return true
+	})
+	return copy
+}
+
+func (c *worldCopy) typeDef(t *TypeDef) *TypeDef {
+	if copy, ok := c.types[t]; ok {
+		return copy
+	}
+	if !c.copied(t) {
+		return t
+	}
+	copy := *t
+	switch owner := t.Owner.(type) {
+	case *World:
+		copy.Owner = c.w
+	case *Interface:
+		copy.Owner = c.ifaces[owner]
+	}
+	c.types[t] = &copy
+	copy.Kind = c.kind(t.Kind)
+	c.res.TypeDefs = append(c.res.TypeDefs, &copy)
+	return &copy
+}
+
+// typ returns t, or a copy of t if it refers to a copied type.
+func (c *worldCopy) typ(t Type) Type {
+	td, ok := t.(*TypeDef)
+	if !ok {
+		return t
+	}
+	if td.Owner != nil {
+		return c.typeDef(td)
+	}
+	kind := c.kind(td.Kind)
+	if kind == td.Kind {
+		return td
+	}
+	copy := &TypeDef{Kind: kind, Stability: td.Stability, Docs: td.Docs}
+	c.res.TypeDefs = append(c.res.TypeDefs, copy)
+	return copy
+}
+
+// kind returns k, or a copy of k if it refers to a copied type.
+func (c *worldCopy) kind(k TypeDefKind) TypeDefKind {
+	changed := func(a, b Type) bool { return a != b }
+	switch k := k.(type) {
+	case *TypeDef:
+		return c.typeDef(k)
+	case *Record:
+		copy := &Record{Fields: slices.Clone(k.Fields)}
+		var ok bool
+		for i := range copy.Fields {
+			copy.Fields[i].Type = c.typ(k.Fields[i].Type)
+			ok = ok || changed(copy.Fields[i].Type, k.Fields[i].Type)
+		}
+		if ok {
+			return copy
+		}
+	case *Tuple:
+		copy := &Tuple{Types: slices.Clone(k.Types)}
+		var ok bool
+		for i := range copy.Types {
+			copy.Types[i] = c.typ(k.Types[i])
+			ok = ok || changed(copy.Types[i], k.Types[i])
+		}
+		if ok {
+			return copy
+		}
+	case *Variant:
+		copy := &Variant{Cases: slices.Clone(k.Cases)}
+		var ok bool
+		for i := range copy.Cases {
+			if k.Cases[i].Type != nil {
+				copy.Cases[i].Type = c.typ(k.Cases[i].Type)
+				ok = ok || changed(copy.Cases[i].Type, k.Cases[i].Type)
+			}
+		}
+		if ok {
+			return copy
+		}
+	case *Result:
+		copy := &Result{OK: c.optional(k.OK), Err: c.optional(k.Err)}
+		if changed(copy.OK, k.OK) || changed(copy.Err, k.Err) {
+			return copy
+		}
+	case *Option:
+		if t := c.typ(k.Type); changed(t, k.Type) {
+			return &Option{Type: t}
+		}
+	case *List:
+		if t := c.typ(k.Type); changed(t, k.Type) {
+			return &List{Type: t}
+		}
+	case *Own:
+		if t := c.typeDef(k.Type); t != k.Type {
+			return &Own{Type: t}
+		}
+	case *Borrow:
+		if t := c.typeDef(k.Type); t != k.Type {
+			return &Borrow{Type: t}
+		}
+	case *Future:
+		if t := c.optional(k.Type); changed(t, k.Type) {
+			return &Future{Type: t}
+		}
+	case *Stream:
+		copy := &Stream{Element: c.optional(k.Element), End: c.optional(k.End)}
+		if changed(copy.Element, k.Element) || changed(copy.End, k.End) {
+			return copy
+		}
+	}
+	return k
+}
+
+// optional returns the copy of optional type t, which may be nil.
+func (c *worldCopy) optional(t Type) Type {
+	if t == nil {
+		return nil
+	}
+	return c.typ(t)
+}
+
+func (c *worldCopy) function(f *Function) *Function {
+	if copy, ok := c.funcs[f]; ok {
+		return copy
+	}
+	copy := *f
+	c.funcs[f] = &copy
+	copy.Params = slices.Clone(f.Params)
+	for i := range copy.Params {
+		copy.Params[i].Type = c.typ(f.Params[i].Type)
+	}
+	copy.Results = slices.Clone(f.Results)
+	for i := range copy.Results {
+		copy.Results[i].Type = c.typ(f.Results[i].Type)
+	}
+	switch kind := f.Kind.(type) {
+	case *Method:
+		copy.Kind = &Method{Type: c.typ(kind.Type)}
+	case *Static:
+		copy.Kind = &Static{Type: c.typ(kind.Type)}
+	case *Constructor:
+		copy.Kind = &Constructor{Type: c.typ(kind.Type)}
+	}
+	return &copy
+}
+
+// declareUse declares a type alias for type n, used from interface from.
+func (r *resolver) declareUse(sc scope, owner TypeOwner, from *Interface, n astUseName, u *astUse) (*TypeDef, error) {
+	name := n.name
+	if n.as != "" {
+		name = n.as
+	}
+	if _, ok := sc[name]; ok {
+		return nil, fmt.Errorf("line %d: duplicate type %s", u.line, name)
+	}
+	t := &TypeDef{Name: &name, Owner: owner, Stability: u.gate}
+	sc[name] = t
+	i, _ := owner.(*Interface)
+	r.uses = append(r.uses, pendingUse{typ: t, owner: i, from: from, name: n.name, line: u.line})
+	return t, nil
+}
+
+func (r *resolver) declareTypeDef(sc scope, owner TypeOwner, a *astTypeDef) (*TypeDef, error) {
+	if _, ok := sc[a.name]; ok {
+		return nil, fmt.Errorf("line %d: duplicate type %s", a.line, a.name)
+	}
+	name := a.name
+	t := &TypeDef{Name: &name, Owner: owner, Stability: a.gate, Docs: a.docs}
+	sc[name] = t
+	r.pending[t] = pendingTypeDef{ast: a, scope: sc}
+	r.declared = append(r.declared, t)
+	return t, nil
+}
+
+func (r *resolver) declareResourceFuncs(sc scope, t *TypeDef, a *astTypeDef) []*Function {
+	var funcs []*Function
+	for _, af := range a.funcs {
+		funcs = append(funcs, r.declareFunc(sc, t, af))
+	}
+	return funcs
+}
+
+func (r *resolver) declareFunc(sc scope, resource *TypeDef, a *astFunc) *Function {
+	f := &Function{Name: a.name, Stability: a.gate, Docs: a.docs}
+	switch a.kind {
+	case "constructor":
+		f.Name = "[constructor]" + resource.TypeName()
+		f.Kind = &Constructor{Type: resource}
+	case "method":
+		f.Name = "[method]" + resource.TypeName() + "." + a.name
+		f.Kind = &Method{Type: resource}
+	case "static":
+		f.Name = "[static]" + resource.TypeName() + "." + a.name
+		f.Kind = &Static{Type: resource}
+	default:
+		f.Kind = &Freestanding{}
+	}
+	r.funcs = append(r.funcs, pendingFunc{fn: f, ast: a, resource: resource, scope: sc})
+	return f
+}
+
+// lookupInterface returns the [Interface] referenced by path, relative to [Package] pkg.
+func (r *resolver) lookupInterface(pkg *Package, path astPath) (*Interface, error) {
+	pkg, err := r.lookupPackage(pkg, path)
+	if err != nil {
+		return nil, err
+	}
+	if i, ok := pkg.Interfaces.GetOK(path.name); ok {
+		return i, nil
+	}
+	return nil, fmt.Errorf("line %d: interface %s not defined in package %s", path.line, path.name, pkg.Name.String())
+}
+
+// lookupWorld returns the [World] referenced by path, relative to [Package] pkg.
+func (r *resolver) lookupWorld(pkg *Package, path astPath) (*World, error) {
+	pkg, err := r.lookupPackage(pkg, path)
+	if err != nil {
+		return nil, err
+	}
+	if w, ok := pkg.Worlds.GetOK(path.name); ok {
+		return w, nil
+	}
+	return nil, fmt.Errorf("line %d: world %s not defined in package %s", path.line, path.name, pkg.Name.String())
+}
+
+// lookupPackage returns the [Package] containing the item referenced by path, relative to [Package] pkg.
+func (r *resolver) lookupPackage(pkg *Package, path astPath) (*Package, error) {
+	if path.pkg == nil {
+		return pkg, nil
+	}
+	var found *Package
+	for _, p := range r.res.Packages {
+		if p.Name.Namespace != path.pkg.Namespace || p.Name.Package != path.pkg.Package {
+			continue
+		}
+		if path.pkg.Version != nil && (p.Name.Version == nil || !p.Name.Version.Equal(*path.pkg.Version)) {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("line %d: ambiguous reference to package %s", path.line, path.pkg.String())
+		}
+		found = p
+	}
+	if found == nil {
+		return nil, fmt.Errorf("line %d: package %s not found", path.line, path.pkg.String())
+	}
+	return found, nil
+}
+
+// sortTypeDefs orders types declared in the same [Interface] or [World] so each type
+// follows the types it refers to, otherwise preserving declaration order, like wasm-tools.
+func (r *resolver) sortTypeDefs(types []*TypeDef) []*TypeDef {
+	index := make(map[*TypeDef]int, len(types))
+	for i, t := range types {
+		index[t] = i
+	}
+	counts := make([]int, len(types))
+	rdeps := make([][]int, len(types))
+	for i, t := range types {
+		pt := r.pending[t]
+		var visit func(a *astType)
+		visit = func(a *astType) {
+			if a == nil {
+				return
+			}
+			if j, ok := index[pt.scope[a.name]]; ok {
+				counts[i]++
+				rdeps[j] = append(rdeps[j], i)
+			}
+			for _, p := range a.params {
+				visit(p)
+			}
+		}
+		visit(pt.ast.typ)
+		for _, f := range pt.ast.fields {
+			visit(f.typ)
+		}
+	}
+
+	// Repeatedly take the first type in declaration order whose dependencies are sorted.
+	sorted := make([]*TypeDef, 0, len(types))
+	done := make([]bool, len(types))
+	for len(sorted) < len(types) {
+		i := 0
+		for i < len(types) && (done[i] || counts[i] > 0) {
+			i++
+		}
+		if i == len(types) {
+			break
+		}
+		done[i] = true
+		sorted = append(sorted, types[i])
+		for _, j := range rdeps[i] {
+			counts[j]--
+		}
+	}
+
+	// Types in a cycle are left for define to report.
+	for i, t := range types {
+		if !done[i] {
+			sorted = append(sorted, t)
+		}
+	}
+	return sorted
+}
+
+// define resolves the kind of [TypeDef] t, if not already resolved.
+func (r *resolver) define(t *TypeDef) error {
+	resolving, ok := r.defined[t]
+	if ok && !resolving {
+		return nil
+	}
+	if resolving {
+		return fmt.Errorf("type %s depends on itself", t.TypeName())
+	}
+	pt, ok := r.pending[t]
+	if !ok {
+		// A use alias, already resolved
+		r.defined[t] = true
+		if next, ok := t.Kind.(*TypeDef); ok {
+			if err := r.define(next); err != nil {
+				return err
+			}
+		}
+		r.defined[t] = false
+		return nil
+	}
+	r.defined[t] = true
+	kind, err := r.resolveKind(pt.ast, pt.scope)
+	if err != nil {
+		return err
+	}
+	t.Kind = kind
+	r.defined[t] = false
+	delete(r.pending, t)
+	r.res.TypeDefs = append(r.res.TypeDefs, t)
+	return nil
+}
+
+func (r *resolver) resolveKind(a *astTypeDef, sc scope) (TypeDefKind, error) {
+	switch a.kind {
+	case "type":
+		return r.resolveTypeKind(a.typ, sc, false)
+	case "resource":
+		return &Resource{}, nil
+	case "record":
+		rec := &Record{}
+		for _, f := range a.fields {
+			t, err := r.resolveType(f.typ, sc)
+			if err != nil {
+				return nil, err
+			}
+			rec.Fields = append(rec.Fields, Field{Name: f.name, Type: t, Docs: f.docs})
+		}
+		return rec, nil
+	case "variant":
+		v := &Variant{}
+		for _, f := range a.fields {
+			c := Case{Name: f.name, Docs: f.docs}
+			if f.typ != nil {
+				t, err := r.resolveType(f.typ, sc)
+				if err != nil {
+					return nil, err
+				}
+				c.Type = t
+			}
+			v.Cases = append(v.Cases, c)
+		}
+		return v, nil
+	case "enum":
+		e := &Enum{}
+		for _, f := range a.fields {
+			e.Cases = append(e.Cases, EnumCase{Name: f.name, Docs: f.docs})
+		}
+		return e, nil
+	case "flags":
+		flags := &Flags{}
+		for _, f := range a.fields {
+			flags.Flags = append(flags.Flags, Flag{Name: f.name, Docs: f.docs})
+		}
+		return flags, nil
+	}
+	return nil, fmt.Errorf("line %d: unknown type definition %s", a.line, a.kind)
+}
+
+// resolveType resolves a into a [Type]. Anonymous types, and references to resources,
+// which are implicitly own<T>, result in a new anonymous [TypeDef].
+func (r *resolver) resolveType(a *astType, sc scope) (Type, error) {
+	kind, err := r.resolveTypeKind(a, sc, true)
+	if err != nil {
+		return nil, err
+	}
+	if t, ok := kind.(Type); ok {
+		return t, nil
+	}
+	t := &TypeDef{Kind: kind}
+	r.res.TypeDefs = append(r.res.TypeDefs, t)
+	return t, nil
+}
+
+// resolveTypeKind resolves a into a [TypeDefKind]. If own is true,
+// a reference to a resource type is resolved as an [Own] handle.
+func (r *resolver) resolveTypeKind(a *astType, sc scope, own bool) (TypeDefKind, error) {
+	arity := func(min, max int) error {
+		if len(a.params) < min || len(a.params) > max {
+			return fmt.Errorf("line %d: wrong number of type parameters for %s", a.line, a.name)
+		}
+		return nil
+	}
+	params := func() ([]Type, error) {
+		var types []Type
+		for _, p := range a.params {
+			if p.name == "_" && !p.explicit {
+				types = append(types, nil)
+				continue
+			}
+			t, err := r.resolveType(p, sc)
+			if err != nil {
+				return nil, err
+			}
+			types = append(types, t)
+		}
+		return types, nil
+	}
+	param := func(types []Type, i int) Type {
+		if i < len(types) {
+			return types[i]
+		}
+		return nil
+	}
+
+	if !a.explicit {
+		switch a.name {
+		case "list", "option", "result", "tuple", "future", "stream":
+			var err error
+			switch a.name {
+			case "list", "option":
+				err = arity(1, 1)
+			case "result":
+				err = arity(0, 2)
+			case "future":
+				err = arity(0, 1)
+			case "stream":
+				err = arity(0, 2)
+			}
+			if err != nil {
+				return nil, err
+			}
+			types, err := params()
+			if err != nil {
+				return nil, err
+			}
+			switch a.name {
+			case "list":
+				return &List{Type: types[0]}, nil
+			case "option":
+				return &Option{Type: types[0]}, nil
+			case "result":
+				return &Result{OK: param(types, 0), Err: param(types, 1)}, nil
+			case "tuple":
+				return &Tuple{Types: types}, nil
+			case "future":
+				return &Future{Type: param(types, 0)}, nil
+			case "stream":
+				return &Stream{Element: param(types, 0), End: param(types, 1)}, nil
+			}
+		case "borrow", "own":
+			if err := arity(1, 1); err != nil {
+				return nil, err
+			}
+			t, err := r.lookupResource(a.params[0], sc)
+			if err != nil {
+				return nil, err
+			}
+			if a.name == "borrow" {
+				return &Borrow{Type: t}, nil
+			}
+			return &Own{Type: t}, nil
+		case "error-context":
+			if err := arity(0, 0); err != nil {
+				return nil, err
+			}
+			return &ErrorContext{}, nil
+		}
+		if t, err := ParseType(a.name); err == nil && len(a.params) == 0 {
+			return t, nil
+		}
+	}
+
+	t, err := r.lookupType(a, sc)
+	if err != nil {
+		return nil, err
+	}
+	if own && isResource(t) {
+		return &Own{Type: t}, nil
+	}
+	return t, nil
+}
+
+func (r *resolver) lookupType(a *astType, sc scope) (*TypeDef, error) {
+	t, ok := sc[a.name]
+	if !ok || len(a.params) != 0 {
+		return nil, fmt.Errorf("line %d: type %s not defined", a.line, a.name)
+	}
+	if err := r.define(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (r *resolver) lookupResource(a *astType, sc scope) (*TypeDef, error) {
+	t, err := r.lookupType(a, sc)
+	if err != nil {
+		return nil, err
+	}
+	if !isResource(t) {
+		return nil, fmt.Errorf("line %d: type %s is not a resource", a.line, a.name)
+	}
+	return t, nil
+}
+
+func isResource(t *TypeDef) bool {
+	_, ok := t.Root().Kind.(*Resource)
+	return ok
+}
+
+func (r *resolver) resolveFunc(pf pendingFunc) error {
+	f := pf.fn
+	if _, ok := f.Kind.(*Method); ok {
+		self := &TypeDef{Kind: &Borrow{Type: pf.resource}}
+		r.res.TypeDefs = append(r.res.TypeDefs, self)
+		f.Params = append(f.Params, Param{Name: "self", Type: self})
+	}
+	for _, p := range pf.ast.params {
+		t, err := r.resolveType(p.typ, pf.scope)
+		if err != nil {
+			return err
+		}
+		f.Params = append(f.Params, Param{Name: p.name, Type: t})
+	}
+	if _, ok := f.Kind.(*Constructor); ok {
+		result := &TypeDef{Kind: &Own{Type: pf.resource}}
+		r.res.TypeDefs = append(r.res.TypeDefs, result)
+		f.Results = []Param{{Type: result}}
+	}
+	for _, p := range pf.ast.results {
+		t, err := r.resolveType(p.typ, pf.scope)
+		if err != nil {
+			return err
+		}
+		f.Results = append(f.Results, Param{Name: p.name, Type: t})
+	}
+	if err := f.validateResults(); err != nil {
+		return fmt.Errorf("line %d: %w", pf.ast.line, err)
+	}
+	return nil
+}
+
+// dependsOn reports whether interface i transitively uses types from interface dep.
+func (r *resolver) dependsOn(i, dep *Interface) bool {
+	if i == nil || dep == nil {
+		return false
+	}
+	for _, d := range r.deps[i] {
+		if d == dep || r.dependsOn(d, dep) {
+			return true
+		}
+	}
+	return false
+}
+
+// elaborateWorld imports any interfaces used by interfaces imported into or exported
+// from [World] w, that are not already imported or exported by w.
+func (r *resolver) elaborateWorld(w *World) {
+	var missing []*Interface
+	var visit func(i *Interface)
+	visit = func(i *Interface) {
+		for _, dep := range r.deps[i] {
+			visit(dep)
+			if !worldHasInterface(w, dep) && !containsInterface(missing, dep) {
+				missing = append(missing, dep)
+			}
+		}
+	}
+	w.AllInterfaces()(func(_ string, i *Interface) bool {
+		visit(i)
+		return true
+	})
+	if len(missing) == 0 {
+		return
+	}
+	var imports ordered.Map[string, WorldItem]
+	for _, i := range missing {
+		imports.Set(interfaceName(i), &InterfaceRef{Interface: i})
+	}
+	w.Imports.All()(func(name string, v WorldItem) bool {
+		imports.Set(name, v)
+		return true
+	})
+	w.Imports = imports
+}
+
+// sortInterfaces returns interfaces sorted topologically by their use dependencies.
+func (r *resolver) sortInterfaces(interfaces []*Interface) []*Interface {
+	sorted := make([]*Interface, 0, len(interfaces))
+	var visit func(i *Interface)
+	visit = func(i *Interface) {
+		if containsInterface(sorted, i) {
+			return
+		}
+		for _, dep := range r.deps[i] {
+			visit(dep)
+		}
+		sorted = append(sorted, i)
+	}
+	for _, i := range interfaces {
+		visit(i)
+	}
+	return sorted
+}
+
+// sortPackages returns packages sorted topologically, dependencies first.
+func sortPackages(packages []*Package) []*Package {
+	sorted := make([]*Package, 0, len(packages))
+	visited := make(map[*Package]bool)
+	var visit func(p *Package)
+	visit = func(p *Package) {
+		if visited[p] {
+			return
+		}
+		visited[p] = true
+		for _, dep := range packages {
+			if dep != p && DependsOn(p, dep) {
+				visit(dep)
+			}
+		}
+		sorted = append(sorted, p)
+	}
+	for _, p := range packages {
+		visit(p)
+	}
+	return sorted
+}
+
+// sameInterfaceRef returns true if a and b are both references to the same [Interface],
+// such as an explicit import of an interface already imported by a use statement.
+func sameInterfaceRef(a, b WorldItem) bool {
+	ra, ok := a.(*InterfaceRef)
+	if !ok {
+		return false
+	}
+	rb, ok := b.(*InterfaceRef)
+	return ok && ra.Interface == rb.Interface
+}
+
+func worldHasInterface(w *World, i *Interface) bool {
+	var found bool
+	w.AllInterfaces()(func(_ string, face *Interface) bool {
+		found = face == i
+		return !found
+	})
+	return found
+}
+
+func containsInterface(list []*Interface, i *Interface) bool {
+	for _, face := range list {
+		if face == i {
+			return true
+		}
+	}
+	return false
+}
+
+// interfaceName returns the fully-qualified name of [Interface] i, e.g. "wasi:io/streams@0.2.0".
+func interfaceName(i *Interface) string {
+	if i.Name == nil {
+		return ""
+	}
+	id := i.Package.Name
+	id.Extension = *i.Name
+	return id.String()
+}
@@ -0,0 +1,94 @@
+package wit
+
+import (
+	"errors"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"go.bytecodealliance.org/internal/relpath"
+)
+
+// TestParseWITGoldenFiles verifies that parsing each golden WIT file with [ParseWIT]
+// and rendering the result produces the same WIT text.
+func TestParseWITGoldenFiles(t *testing.T) {
+	var parsed, unsupported int
+	err := relpath.Walk(testdataPath, func(path string) error {
+		t.Run(path, func(t *testing.T) {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			res, err := ParseWIT(strings.NewReader(string(data)))
+			if errors.Is(err, errors.ErrUnsupported) {
+				unsupported++
+				t.Log(err)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			parsed++
+			// The order of nested packages is not significant
+			got := sortPackageWIT(res.WIT(nil, ""))
+			want := sortPackageWIT(string(data))
+			if got != want {
+				t.Errorf("ParseWIT(%s): rendered WIT did not match:\n%s", path, got)
+			}
+		})
+		return nil
+	}, "*.golden.wit")
+	if err != nil {
+		t.Error(err)
+	}
+	t.Logf("parsed %d files, %d unsupported", parsed, unsupported)
+}
+
+func TestParseWITErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		wit  string
+	}{
+		{"empty input", ``},
+		{"comments only", `// nothing here`},
+		{"duplicate function", `package foo:bar; interface i { f: func(); f: func(); }`},
+		{"duplicate method", `package foo:bar; interface i { resource r { f: func(); f: func(); } }`},
+		{"duplicate constructor", `package foo:bar; interface i { resource r { constructor(); constructor(a: u32); } }`},
+		{"duplicate world method", `package foo:bar; world w { resource r { f: func(); f: func(); } }`},
+		{"world type after function", `package foo:bar; world w { import t: func(); type t = u32; }`},
+		{"world use after function", `package foo:bar; interface i { type t = u32; } world w { import t: func(); use i.{t}; }`},
+		{"duplicate param", `package foo:bar; interface i { f: func(a: u32, a: u32); }`},
+		{"duplicate result", `package foo:bar; interface i { f: func() -> (a: u32, a: u32); }`},
+		{"empty variant", `package foo:bar; interface i { variant v {} }`},
+		{"empty enum", `package foo:bar; interface i { enum e {} }`},
+		{"result without error type", `package foo:bar; interface i { type t = result<_>; }`},
+		{"result with two placeholders", `package foo:bar; interface i { type t = result<_, _>; }`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := ParseWIT(strings.NewReader(tt.wit))
+			if err == nil {
+				t.Errorf("ParseWIT: expected error, got nil:\n%s", res.WIT(nil, ""))
+			}
+		})
+	}
+}
+
+// sortPackageWIT sorts the nested packages in WIT text s, after the first (root) package.
+func sortPackageWIT(s string) string {
+	var pkgs []string
+	for _, line := range strings.SplitAfter(s, "\n") {
+		if strings.HasPrefix(line, "package ") || len(pkgs) == 0 {
+			pkgs = append(pkgs, "")
+		}
+		pkgs[len(pkgs)-1] += line
+	}
+	for i := range pkgs {
+		pkgs[i] = strings.TrimSpace(pkgs[i])
+	}
+	if len(pkgs) > 1 {
+		slices.Sort(pkgs[1:])
+	}
+	return strings.Join(pkgs, "\n\n")
+}
@@ -0,0 +1,166 @@
+package wit
+
+import "strconv"
+
+// OpKind is the kind of an [Op] in a plan returned by [Function.LiftLowerPlan].
+type OpKind int
+
+const (
+	// OpLower lowers a WIT value into one or more flat Core WebAssembly values.
+	OpLower OpKind = iota
+
+	// OpLift lifts a WIT value from one or more flat Core WebAssembly values.
+	OpLift
+
+	// OpAlloc allocates Size bytes of linear memory aligned to Align,
+	// for parameters or results that do not fit in flat values.
+	OpAlloc
+
+	// OpStore lowers a WIT value by storing it in linear memory at Offset
+	// from the start of the most recent allocation.
+	OpStore
+
+	// OpLoad lifts a WIT value by loading it from linear memory at Offset
+	// from the start of the parameter or result area.
+	OpLoad
+
+	// OpCall calls the function. Operations before OpCall prepare its parameters,
+	// and operations after OpCall handle its results.
+	OpCall
+)
+
+// String implements [fmt.Stringer], returning the name of the operation, e.g. "lower" or "load".
+func (k OpKind) String() string {
+	switch k {
+	case OpLower:
+		return "lower"
+	case OpLift:
+		return "lift"
+	case OpAlloc:
+		return "alloc"
+	case OpStore:
+		return "store"
+	case OpLoad:
+		return "load"
+	case OpCall:
+		return "call"
+	}
+	return strconv.Itoa(int(k))
+}
+
+// Op is a single marshaling operation in a plan returned by [Function.LiftLowerPlan].
+type Op struct {
+	Kind OpKind
+
+	// Name is the name of the parameter or result, e.g. "self" or "result".
+	// For OpAlloc, it is "params" or "results". It is empty for OpCall.
+	Name string
+
+	// Result is true if the operation applies to a result of the function, otherwise a parameter.
+	Result bool
+
+	// Type is the WIT type of the value lifted, lowered, loaded, or stored.
+	Type Type
+
+	// Flat is the flat Core WebAssembly representation of Type, for OpLift and OpLower.
+	Flat []Type
+
+	// Offset is the byte offset of the value in linear memory, for OpLoad and OpStore.
+	Offset uintptr
+
+	// Size and Align are the byte size and alignment of the memory allocated by OpAlloc.
+	Size, Align uintptr
+}
+
+// LiftLowerPlan returns the ordered [Canonical ABI] operations to call [Function] f
+// in [Direction] dir, following the flattening rules of [Function.CoreFunction].
+//
+// For an [Imported] function, the caller lowers each parameter into flat values,
+// or, if there are more than [MaxFlatParams] flat values, allocates memory and
+// stores each parameter in it. After the call, the caller lifts the result from
+// a flat value, or, if there is more than [MaxFlatResults] flat value, loads the
+// results from a return area allocated before the call.
+//
+// For an [Exported] function, the callee lifts each parameter from flat values,
+// or loads each parameter from memory allocated by the caller. After the call, the callee
+// lowers the result into a flat value, or allocates a return area and stores the results in it.
+// The return area is freed by the [Function.PostReturn] function.
+//
+// [Canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md
+func (f *Function) LiftLowerPlan(dir Direction) []Op {
+	var ops []Op
+	if dir == Imported {
+		ops = appendPlan(ops, f.Params, false, MaxFlatParams, OpLower, OpStore, true)
+		if flatCount(f.Results) > MaxFlatResults {
+			ops = append(ops, allocOp(f.Results, true))
+		}
+		ops = append(ops, Op{Kind: OpCall})
+		ops = appendPlan(ops, f.Results, true, MaxFlatResults, OpLift, OpLoad, false)
+	} else {
+		ops = appendPlan(ops, f.Params, false, MaxFlatParams, OpLift, OpLoad, false)
+		ops = append(ops, Op{Kind: OpCall})
+		ops = appendPlan(ops, f.Results, true, MaxFlatResults, OpLower, OpStore, true)
+	}
+	return ops
+}
+
+// appendPlan appends operations for params to ops, using flat if params have at most
+// maxFlat flat values, and otherwise mem, preceded by an OpAlloc if alloc is true.
+func appendPlan(ops []Op, params []Param, result bool, maxFlat int, flat, mem OpKind, alloc bool) []Op {
+	if len(params) == 0 {
+		return ops
+	}
+	if flatCount(params) <= maxFlat {
+		for _, p := range params {
+			ops = append(ops, Op{Kind: flat, Name: paramName(p, result), Result: result, Type: p.Type, Flat: p.Type.Flat()})
+		}
+		return ops
+	}
+	if alloc {
+		ops = append(ops, allocOp(params, result))
+	}
+	if len(params) == 1 {
+		p := params[0]
+		return append(ops, Op{Kind: mem, Name: paramName(p, result), Result: result, Type: p.Type})
+	}
+	for i, fo := range paramsRecord(params).Layout() {
+		ops = append(ops, Op{Kind: mem, Name: paramName(params[i], result), Result: result, Type: fo.Field.Type, Offset: fo.Offset})
+	}
+	return ops
+}
+
+// allocOp returns an OpAlloc for the memory needed to store params.
+func allocOp(params []Param, result bool) Op {
+	var t ABI = paramsRecord(params)
+	if len(params) == 1 {
+		t = params[0].Type
+	}
+	name := "params"
+	if result {
+		name = "results"
+	}
+	return Op{Kind: OpAlloc, Name: name, Result: result, Size: t.Size(), Align: t.Align()}
+}
+
+func paramsRecord(params []Param) *Record {
+	r := &Record{Fields: make([]Field, len(params))}
+	for i, p := range params {
+		r.Fields[i] = Field{Name: p.Name, Type: p.Type}
+	}
+	return r
+}
+
+func flatCount(params []Param) int {
+	n := 0
+	for _, p := range params {
+		n += len(p.Type.Flat())
+	}
+	return n
+}
+
+func paramName(p Param, result bool) string {
+	if p.Name == "" && result {
+		return "result"
+	}
+	return p.Name
+}
@@ -0,0 +1,65 @@
+package wit
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestFunctionLiftLowerPlan(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:bar;
+
+interface i {
+	none: func();
+	small: func(a: u32, s: string) -> u32;
+	spill-results: func(a: u32) -> string;
+	named-results: func() -> (a: u8, b: u64);
+	spill-params: func(a: u64, b: u64, c: u64, d: u64, e: u64, f: u64, g: u64, h: u64, i: u64, j: u64, k: u64, l: u64, m: u64, n: u64, o: u64, p: u64, q: u8);
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := res.Interfaces[0]
+	tests := []struct {
+		name string
+		dir  Direction
+		want string
+	}{
+		{"none", Imported, "call"},
+		{"none", Exported, "call"},
+		{"small", Imported, "lower a [u32], lower s [pointer u32], call, lift result [u32]"},
+		{"small", Exported, "lift a [u32], lift s [pointer u32], call, lower result [u32]"},
+		{"spill-results", Imported, "lower a [u32], alloc results 8/4, call, load result @0"},
+		{"spill-results", Exported, "lift a [u32], call, alloc results 8/4, store result @0"},
+		{"named-results", Imported, "alloc results 16/8, call, load a @0, load b @8"},
+		{"named-results", Exported, "call, alloc results 16/8, store a @0, store b @8"},
+		{"spill-params", Imported, "alloc params 136/8, store a @0, store b @8, store c @16, store d @24, store e @32, store f @40, store g @48, store h @56, store i @64, store j @72, store k @80, store l @88, store m @96, store n @104, store o @112, store p @120, store q @128, call"},
+		{"spill-params", Exported, "load a @0, load b @8, load c @16, load d @24, load e @32, load f @40, load g @48, load h @56, load i @64, load j @72, load k @80, load l @88, load m @96, load n @104, load o @112, load p @120, load q @128, call"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+"/"+tt.dir.String(), func(t *testing.T) {
+			f := i.Functions.Get(tt.name)
+			var got []string
+			for _, op := range f.LiftLowerPlan(tt.dir) {
+				s := op.Kind.String()
+				switch op.Kind {
+				case OpLift, OpLower:
+					var flat []string
+					for _, t := range op.Flat {
+						flat = append(flat, t.WITKind())
+					}
+					s += " " + op.Name + " [" + strings.Join(flat, " ") + "]"
+				case OpAlloc:
+					s += " " + op.Name + " " + strconv.Itoa(int(op.Size)) + "/" + strconv.Itoa(int(op.Align))
+				case OpLoad, OpStore:
+					s += " " + op.Name + " @" + strconv.Itoa(int(op.Offset))
+				}
+				got = append(got, s)
+			}
+			if s := strings.Join(got, ", "); s != tt.want {
+				t.Errorf("LiftLowerPlan(%v):\n%s\nexpected:\n%s", tt.dir, s, tt.want)
+			}
+		})
+	}
+}
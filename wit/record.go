@@ -9,6 +9,12 @@ type Record struct {
 	Fields []Field
 }
 
+// IsEmpty returns true if [Record] r has no fields. An empty record is valid,
+// with a size of 0 and an alignment of 1, and is represented in Go as an empty struct.
+func (r *Record) IsEmpty() bool {
+	return len(r.Fields) == 0
+}
+
 // Size returns the [ABI byte size] for [Record] r.
 //
 // [ABI byte size]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#size
@@ -18,7 +24,7 @@ func (r *Record) Size() uintptr {
 		s = Align(s, f.Type.Align())
 		s += f.Type.Size()
 	}
-	return s
+	return Align(s, r.Align())
 }
 
 // Align returns the [ABI byte alignment] for [Record] r.
@@ -32,6 +38,27 @@ func (r *Record) Align() uintptr {
 	return a
 }
 
+// Layout returns the [ABI memory layout] of each field in [Record] r, in declaration order.
+// Each field is aligned to its [ABI byte alignment], inserting padding between fields as necessary.
+//
+// [ABI memory layout]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#storing
+// [ABI byte alignment]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#alignment
+func (r *Record) Layout() []FieldOffset {
+	layout := make([]FieldOffset, len(r.Fields))
+	var s uintptr
+	for i := range r.Fields {
+		f := &r.Fields[i]
+		s = Align(s, f.Type.Align())
+		layout[i] = FieldOffset{
+			Field:  f,
+			Offset: s,
+			Size:   f.Type.Size(),
+		}
+		s += f.Type.Size()
+	}
+	return layout
+}
+
 // Flat returns the [flattened] ABI representation of [Record] r.
 //
 // [flattened]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#flattening
@@ -88,3 +115,17 @@ type Field struct {
 	Type Type
 	Docs Docs
 }
+
+// WITName returns the WIT name of [Field] f, which is its Name, e.g. "wall-clock".
+// Code generators can use it in struct tags to preserve the original name.
+func (f *Field) WITName() string {
+	return f.Name
+}
+
+// FieldOffset describes the position of a [Field] within the
+// Canonical ABI memory layout of a [Record].
+type FieldOffset struct {
+	Field  *Field
+	Offset uintptr // byte offset from the start of the record
+	Size   uintptr // byte size of the field
+}
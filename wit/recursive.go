@@ -0,0 +1,125 @@
+package wit
+
+import "slices"
+
+// RecursiveTypes returns the set of [TypeDef] values in [Resolve] r that are part of a cycle
+// in the type reference graph, computed with [Tarjan's algorithm] for strongly connected components.
+// A type that refers directly to itself is also included.
+//
+// Each type refers to the types in its [TypeDefKind], e.g. the fields of a [Record].
+// A [Resource] also refers to the parameter and result types of its constructor,
+// methods, and static functions, excluding handles to the resource itself,
+// such as the implicit self parameter of a method.
+// For example, a record with a field of type own<r>, where resource r has a method
+// that returns the record, is recursive.
+//
+// Code generators can use the returned set to determine where to insert indirection,
+// such as a pointer, to represent a recursive type.
+//
+// [Tarjan's algorithm]: https://en.wikipedia.org/wiki/Tarjan%27s_strongly_connected_components_algorithm
+func (r *Resolve) RecursiveTypes() map[*TypeDef]bool {
+	edges := make(map[*TypeDef][]*TypeDef)
+	addEdges := func(from *TypeDef, types ...Type) {
+		for _, t := range types {
+			if td, ok := t.(*TypeDef); ok && td != nil {
+				edges[from] = append(edges[from], td)
+			}
+		}
+	}
+	for _, t := range r.TypeDefs {
+		addEdges(t, kindTypes(t.Kind)...)
+	}
+	r.AllFunctions()(func(f *Function) bool {
+		t, ok := f.ResourceType()
+		if !ok {
+			return true
+		}
+		owner, ok := t.(*TypeDef)
+		if !ok {
+			return true
+		}
+		for _, p := range slices.Concat(f.Params, f.Results) {
+			if !isHandleTo(p.Type, owner) {
+				addEdges(owner, p.Type)
+			}
+		}
+		return true
+	})
+
+	s := &scc{
+		edges:     edges,
+		index:     make(map[*TypeDef]int),
+		lowlink:   make(map[*TypeDef]int),
+		onStack:   make(map[*TypeDef]bool),
+		recursive: make(map[*TypeDef]bool),
+	}
+	for _, t := range r.TypeDefs {
+		if _, ok := s.index[t]; !ok {
+			s.connect(t)
+		}
+	}
+	return s.recursive
+}
+
+// isHandleTo returns true if t is an [Own] or [Borrow] handle to resource.
+func isHandleTo(t Type, resource *TypeDef) bool {
+	td, ok := t.(*TypeDef)
+	if !ok {
+		return false
+	}
+	switch h := td.Kind.(type) {
+	case *Own:
+		return h.Type == resource
+	case *Borrow:
+		return h.Type == resource
+	}
+	return false
+}
+
+// scc holds the state of Tarjan's strongly connected components algorithm.
+type scc struct {
+	edges     map[*TypeDef][]*TypeDef
+	next      int
+	index     map[*TypeDef]int
+	lowlink   map[*TypeDef]int
+	stack     []*TypeDef
+	onStack   map[*TypeDef]bool
+	recursive map[*TypeDef]bool
+}
+
+func (s *scc) connect(t *TypeDef) {
+	s.index[t] = s.next
+	s.lowlink[t] = s.next
+	s.next++
+	s.stack = append(s.stack, t)
+	s.onStack[t] = true
+
+	for _, dep := range s.edges[t] {
+		if _, ok := s.index[dep]; !ok {
+			s.connect(dep)
+			s.lowlink[t] = min(s.lowlink[t], s.lowlink[dep])
+		} else if s.onStack[dep] {
+			s.lowlink[t] = min(s.lowlink[t], s.index[dep])
+		}
+		if dep == t {
+			s.recursive[t] = true
+		}
+	}
+
+	if s.lowlink[t] != s.index[t] {
+		return
+	}
+	// t is the root of a strongly connected component
+	i := len(s.stack) - 1
+	for s.stack[i] != t {
+		i--
+	}
+	component := s.stack[i:]
+	s.stack = s.stack[:i]
+	for _, c := range component {
+		s.onStack[c] = false
+		if len(component) > 1 {
+			s.recursive[c] = true
+		}
+	}
+}
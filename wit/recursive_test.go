@@ -0,0 +1,66 @@
+package wit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRecursiveTypes(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:bar;
+
+interface i {
+	resource node {
+		constructor(name: string);
+		children: func() -> tree;
+		is-leaf: func() -> bool;
+	}
+	record tree {
+		children: list<own<node>>,
+	}
+	resource leaf {
+		constructor();
+		clone: func() -> leaf;
+	}
+	record point { x: u32, y: u32 }
+	type points = list<point>;
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	types := res.Interfaces[0].TypeDefs
+	got := res.RecursiveTypes()
+	for _, name := range []string{"node", "tree"} {
+		if !got[types.Get(name)] {
+			t.Errorf("RecursiveTypes(): expected %s", name)
+		}
+	}
+	for _, name := range []string{"leaf", "point", "points"} {
+		if got[types.Get(name)] {
+			t.Errorf("RecursiveTypes(): unexpected %s", name)
+		}
+	}
+	var named int
+	for td := range got {
+		if td.Name != nil {
+			named++
+		}
+	}
+	if named != 2 {
+		t.Errorf("RecursiveTypes(): %d named types, expected 2", named)
+	}
+}
+
+func TestRecursiveTypesSelfReference(t *testing.T) {
+	list := &TypeDef{}
+	self := &TypeDef{Kind: &Option{Type: list}}
+	list.Kind = &List{Type: self}
+	alias := &TypeDef{}
+	alias.Kind = alias
+	other := &TypeDef{Kind: &List{Type: U8{}}}
+	res := &Resolve{TypeDefs: []*TypeDef{list, self, alias, other}}
+	got := res.RecursiveTypes()
+	if !got[list] || !got[self] || !got[alias] || got[other] {
+		t.Errorf("RecursiveTypes(): %v", got)
+	}
+}
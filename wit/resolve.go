@@ -1,7 +1,11 @@
 package wit
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"slices"
+	"strings"
 
 	"go.bytecodealliance.org/wit/iterate"
 )
@@ -21,6 +25,18 @@ type Resolve struct {
 	Interfaces []*Interface
 	TypeDefs   []*TypeDef
 	Packages   []*Package
+
+	// ActiveFeatures lists the WIT features that were enabled when the JSON was produced,
+	// as recorded in the optional "features" array of the JSON, to distinguish a view gated
+	// by @unstable features from an all-features view. It is nil if the JSON does not record features.
+	ActiveFeatures []string
+
+	// raw holds the JSON for each decoded item if decoded with [RetainRawJSON].
+	raw map[any]json.RawMessage
+
+	// errorContext is the anonymous error-context TypeDef shared by types decoded
+	// from the primitive "error-context" form. [DecodeJSON] appends it to TypeDefs.
+	errorContext *TypeDef
 }
 
 // Clone returns a shallow clone of r.
@@ -30,9 +46,288 @@ func (r *Resolve) Clone() *Resolve {
 	c.Interfaces = slices.Clone(r.Interfaces)
 	c.TypeDefs = slices.Clone(r.TypeDefs)
 	c.Packages = slices.Clone(r.Packages)
+	c.ActiveFeatures = slices.Clone(r.ActiveFeatures)
 	return &c
 }
 
+// Package returns the [Package] in [Resolve] r with the namespace, package name, and version of name.
+// The Extension of name is ignored. If name has no version, Package matches a package with any version,
+// but only if exactly one version of the package is present in r.
+// It returns false if no package matches.
+func (r *Resolve) Package(name Ident) (*Package, bool) {
+	var found *Package
+	for _, p := range r.Packages {
+		if p.Name.Namespace != name.Namespace || p.Name.Package != name.Package {
+			continue
+		}
+		if name.Version != nil {
+			if p.Name.Version != nil && p.Name.Version.Equal(*name.Version) {
+				return p, true
+			}
+			continue
+		}
+		if found != nil {
+			return nil, false // ambiguous
+		}
+		found = p
+	}
+	return found, found != nil
+}
+
+// ResolvePackageRef returns the [Package] in [Resolve] r referred to by ref, e.g. "wasi:clocks"
+// or "wasi:clocks@0.2.0", as typically provided on a command line. Namespaces and package names
+// are matched case-insensitively, and any extension in ref, e.g. "/wall-clock", is ignored.
+//
+// If ref has no version, it matches the only version of the package in r, or the unversioned
+// package if r has both versioned and unversioned packages of that name. If ref has a version,
+// the package version must be equal.
+// The returned error wraps [ErrPackageNotFound] if no package matches, or [ErrAmbiguousPackage]
+// if more than one version matches, and lists the available versions.
+func (r *Resolve) ResolvePackageRef(ref string) (*Package, error) {
+	id, err := ParseIdent(ref)
+	if err != nil {
+		return nil, err
+	}
+	var matches []*Package
+	for _, p := range r.Packages {
+		if strings.EqualFold(p.Name.Namespace, id.Namespace) && strings.EqualFold(p.Name.Package, id.Package) {
+			matches = append(matches, p)
+		}
+	}
+	names := func() string {
+		s := make([]string, len(matches))
+		for i, p := range matches {
+			s[i] = p.Name.String()
+		}
+		return strings.Join(s, ", ")
+	}
+	id.Extension = ""
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrPackageNotFound, id.String())
+	}
+	if id.Version != nil {
+		for _, p := range matches {
+			if p.Name.Version != nil && p.Name.Version.Equal(*id.Version) {
+				return p, nil
+			}
+		}
+		return nil, fmt.Errorf("%w: %s (found %s)", ErrPackageNotFound, id.String(), names())
+	}
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+	var unversioned []*Package
+	for _, p := range matches {
+		if p.Name.Version == nil {
+			unversioned = append(unversioned, p)
+		}
+	}
+	if len(unversioned) == 1 {
+		return unversioned[0], nil
+	}
+	return nil, fmt.Errorf("%w: %s matches %s; specify a version", ErrAmbiguousPackage, id.String(), names())
+}
+
+// RenamePackage renames the [Package] in [Resolve] r named old to name, as matched by [Resolve.Package].
+// Worlds, interfaces, and types refer to their package by pointer, so references to the package
+// throughout r, including qualified names in WIT output, reflect the new name.
+// It returns an error if old is not found, if name is invalid or has an extension,
+// or if a package named name already exists in r.
+func (r *Resolve) RenamePackage(old, name Ident) error {
+	p, ok := r.Package(old)
+	if !ok {
+		return fmt.Errorf("package %s not found", old.String())
+	}
+	if err := name.Validate(); err != nil {
+		return err
+	}
+	if name.Extension != "" {
+		return fmt.Errorf("%w: package name %s has an extension", ErrInvalidPackageName, name.String())
+	}
+	for _, other := range r.Packages {
+		if other != p && other.Name.String() == name.String() {
+			return fmt.Errorf("package %s already exists", name.String())
+		}
+	}
+	p.Name = name
+	return nil
+}
+
+// AddInterface adds [Interface] iface, for example a host-provided interface not described
+// in WIT, to the [Package] in [Resolve] r named pkg, creating the package if needed.
+// Types in iface without an owner are linked to it, and its types, along with any
+// anonymous types they or its functions refer to, are appended to r.TypeDefs after the
+// types they refer to. It returns iface, or an error if iface has no name, if pkg is invalid
+// or has an extension, or if the package already has an interface of the same name.
+func (r *Resolve) AddInterface(pkg Ident, iface *Interface) (*Interface, error) {
+	if iface.Name == nil {
+		return nil, errors.New("cannot add an anonymous interface")
+	}
+	if err := pkg.Validate(); err != nil {
+		return nil, err
+	}
+	if pkg.Extension != "" {
+		return nil, fmt.Errorf("%w: package name %s has an extension", ErrInvalidPackageName, pkg.String())
+	}
+	var p *Package
+	for _, other := range r.Packages {
+		if other.Name.String() == pkg.String() {
+			p = other
+			break
+		}
+	}
+	if p == nil {
+		p = &Package{Name: pkg}
+		r.Packages = append(r.Packages, p)
+	} else if _, ok := p.Interfaces.GetOK(*iface.Name); ok {
+		return nil, fmt.Errorf("duplicate interface %s in package %s", *iface.Name, pkg.String())
+	}
+
+	iface.Package = p
+	p.Interfaces.Set(*iface.Name, iface)
+	r.Interfaces = append(r.Interfaces, iface)
+
+	n := &normalizer{types: make(map[*TypeDef]bool)}
+	for _, t := range r.TypeDefs {
+		n.types[t] = true
+	}
+	iface.TypeDefs.All()(func(name string, t *TypeDef) bool {
+		if t.Name == nil {
+			t.Name = &name
+		}
+		if t.Owner == nil {
+			t.Owner = iface
+		}
+		n.visitType(t)
+		return true
+	})
+	iface.Functions.All()(func(_ string, f *Function) bool {
+		n.visitFunction(f)
+		return true
+	})
+	r.TypeDefs = append(r.TypeDefs, n.sortedTypes...)
+	return iface, nil
+}
+
+// IndexOf returns the index of [TypeDef] t in r.TypeDefs and true, or -1 and false if not found.
+// After [DecodeJSON], the index of each TypeDef is its index in the JSON "types" array,
+// which is used for references between types in the JSON encoding.
+// The one exception is the anonymous error-context TypeDef that DecodeJSON creates for
+// types referring to the primitive "error-context", which has no entry in the JSON "types" array.
+// It is appended after the decoded types, so its index is equal to the length of that array.
+func (r *Resolve) IndexOf(t *TypeDef) (int, bool) {
+	i := slices.Index(r.TypeDefs, t)
+	return i, i >= 0
+}
+
+// TypeDefAt returns the [TypeDef] at index i in r.TypeDefs, or nil if i is out of range.
+// It is the inverse of [Resolve.IndexOf].
+func (r *Resolve) TypeDefAt(i int) *TypeDef {
+	if i < 0 || i >= len(r.TypeDefs) {
+		return nil
+	}
+	return r.TypeDefs[i]
+}
+
+// AnonymousTypes returns the anonymous types in [Resolve] r, such as list<u32>, in the order of r.TypeDefs.
+// An anonymous type has a nil Name, and is defined inline where it is used.
+//
+// Structurally identical anonymous types are not necessarily the same [TypeDef].
+// After [DecodeJSON], each package has at most one of each, as wasm-tools deduplicates
+// anonymous types within a package, but not across packages: list<u8> may appear once per package.
+// [ParseWIT] does not deduplicate anonymous types. Code generators that reuse generated types
+// should therefore compare anonymous types by structure rather than by pointer.
+func (r *Resolve) AnonymousTypes() []*TypeDef {
+	var types []*TypeDef
+	for _, t := range r.TypeDefs {
+		if t.Name == nil {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// NamedTypes returns the named types in [Resolve] r, including type aliases, in the order of r.TypeDefs.
+// See [Resolve.AnonymousTypes].
+func (r *Resolve) NamedTypes() []*TypeDef {
+	var types []*TypeDef
+	for _, t := range r.TypeDefs {
+		if t.Name != nil {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// DuplicateTypes returns groups of two or more named types in [Resolve] r that are structurally equal,
+// such as identical error enums defined in several interfaces or packages. Type names are not
+// compared, so a group may contain types with different names. Within a type, references to
+// named types are equal if the referenced types have the same name and their owners have
+// the same unversioned name, as in [Diff].
+//
+// Resources are never duplicates, as each resource type is distinct. Type aliases, such as
+// "type size = u64" or types imported with use statements, are not included, as they are not
+// separate definitions. See [TypeDef.IsAlias].
+// Groups and the types within them are in the order of r.TypeDefs.
+func (r *Resolve) DuplicateTypes() [][]*TypeDef {
+	var groups [][]*TypeDef
+	for _, t := range r.TypeDefs {
+		if t.Name == nil || t.IsAlias() {
+			continue
+		}
+		if _, ok := t.Kind.(*Resource); ok {
+			continue
+		}
+		i := slices.IndexFunc(groups, func(g []*TypeDef) bool { return sameKind(g[0].Kind, t.Kind) })
+		if i < 0 {
+			groups = append(groups, []*TypeDef{t})
+		} else {
+			groups[i] = append(groups[i], t)
+		}
+	}
+	return slices.DeleteFunc(groups, func(g []*TypeDef) bool { return len(g) < 2 })
+}
+
+// StripDocs removes the documentation from every [Package], [World], [Interface], [TypeDef],
+// and [Function] in [Resolve] r, and from each [Field], [Case], [EnumCase], and [Flag] of a type.
+// It modifies r in place.
+func (r *Resolve) StripDocs() {
+	for _, p := range r.Packages {
+		p.Docs = Docs{}
+	}
+	for _, w := range r.Worlds {
+		w.Docs = Docs{}
+	}
+	for _, i := range r.Interfaces {
+		i.Docs = Docs{}
+	}
+	for _, t := range r.TypeDefs {
+		t.Docs = Docs{}
+		switch kind := t.Kind.(type) {
+		case *Record:
+			for i := range kind.Fields {
+				kind.Fields[i].Docs = Docs{}
+			}
+		case *Variant:
+			for i := range kind.Cases {
+				kind.Cases[i].Docs = Docs{}
+			}
+		case *Enum:
+			for i := range kind.Cases {
+				kind.Cases[i].Docs = Docs{}
+			}
+		case *Flags:
+			for i := range kind.Flags {
+				kind.Flags[i].Docs = Docs{}
+			}
+		}
+	}
+	r.AllFunctions()(func(f *Function) bool {
+		f.Docs = Docs{}
+		return true
+	})
+}
+
 // AllFunctions returns a [sequence] that yields each [Function] in a [Resolve].
 // The sequence stops if yield returns false.
 //
@@ -0,0 +1,425 @@
+package wit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestResolvePackage(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:root;
+
+package foo:bar@1.0.0 {
+	interface i {}
+}
+
+package foo:bar@2.0.0 {
+	interface i {}
+}
+
+package foo:baz@0.1.0 {
+	interface i {}
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"foo:root", "foo:root"},
+		{"foo:root@1.0.0", ""},
+		{"foo:bar@1.0.0", "foo:bar@1.0.0"},
+		{"foo:bar@2.0.0", "foo:bar@2.0.0"},
+		{"foo:bar@3.0.0", ""},
+		{"foo:bar", ""}, // ambiguous
+		{"foo:baz", "foo:baz@0.1.0"},
+		{"foo:baz/i", "foo:baz@0.1.0"},
+		{"foo:qux", ""},
+		{"bar:baz", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, err := ParseIdent(tt.name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p, ok := res.Package(id)
+			var got string
+			if p != nil {
+				got = p.Name.String()
+			}
+			if got != tt.want || ok != (tt.want != "") {
+				t.Errorf("Package(%s): %q, %t, expected %q", tt.name, got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveResolvePackageRef(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:root;
+
+package foo:bar@1.0.0 {
+	interface i {}
+}
+
+package foo:bar@2.0.0-rc.1 {
+	interface i {}
+}
+
+package foo:baz@0.1.0 {
+	interface i {}
+}
+
+package foo:qux {
+	interface i {}
+}
+
+package foo:qux@1.0.0 {
+	interface i {}
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr error
+	}{
+		{"foo:root", "foo:root", nil},
+		{"FOO:Root", "foo:root", nil},
+		{"foo:baz", "foo:baz@0.1.0", nil},
+		{"foo:baz/i", "foo:baz@0.1.0", nil},
+		{"foo:bar@1.0.0", "foo:bar@1.0.0", nil},
+		{"foo:bar@2.0.0-rc.1", "foo:bar@2.0.0-rc.1", nil},
+		{"foo:qux", "foo:qux", nil},
+		{"foo:qux@1.0.0", "foo:qux@1.0.0", nil},
+		{"foo:bar", "", ErrAmbiguousPackage},
+		{"foo:bar@3.0.0", "", ErrPackageNotFound},
+		{"foo:root@1.0.0", "", ErrPackageNotFound},
+		{"foo:missing", "", ErrPackageNotFound},
+		{"foo", "", ErrInvalidPackageName},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			p, err := res.ResolvePackageRef(tt.ref)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ResolvePackageRef(%q): %v, expected %v", tt.ref, err, tt.wantErr)
+			}
+			var got string
+			if p != nil {
+				got = p.Name.String()
+			}
+			if got != tt.want {
+				t.Errorf("ResolvePackageRef(%q): %q, expected %q", tt.ref, got, tt.want)
+			}
+		})
+	}
+	_, err = res.ResolvePackageRef("foo:bar")
+	if want := "foo:bar@1.0.0, foo:bar@2.0.0-rc.1"; err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("ResolvePackageRef(foo:bar): %v, expected error listing %s", err, want)
+	}
+}
+
+func TestResolveIndexOf(t *testing.T) {
+	path := filepath.Join(testdataPath, "wasi/cli.wit.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var raw struct {
+		Types []struct {
+			Name *string `json:"name"`
+		} `json:"types"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		t.Fatal(err)
+	}
+	res, err := DecodeJSON(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(raw.Types) != len(res.TypeDefs) {
+		t.Fatalf("JSON has %d types, Resolve has %d", len(raw.Types), len(res.TypeDefs))
+	}
+	for i, rt := range raw.Types {
+		td := res.TypeDefAt(i)
+		if td == nil {
+			t.Fatalf("TypeDefAt(%d): nil", i)
+		}
+		if (rt.Name == nil) != (td.Name == nil) || (rt.Name != nil && *rt.Name != *td.Name) {
+			t.Errorf("TypeDefAt(%d): name %v, expected %v", i, td.Name, rt.Name)
+		}
+		if got, ok := res.IndexOf(td); got != i || !ok {
+			t.Errorf("IndexOf(TypeDefAt(%d)): %d, %t", i, got, ok)
+		}
+	}
+	for _, i := range []int{-1, len(res.TypeDefs)} {
+		if td := res.TypeDefAt(i); td != nil {
+			t.Errorf("TypeDefAt(%d): %v, expected nil", i, td)
+		}
+	}
+	if i, ok := res.IndexOf(&TypeDef{}); i != -1 || ok {
+		t.Errorf("IndexOf(unknown): %d, %t, expected -1, false", i, ok)
+	}
+}
+
+func TestResolveRenamePackage(t *testing.T) {
+	parse := func() *Resolve {
+		res, err := ParseWIT(strings.NewReader(`package app:main;
+
+world w {
+	import my:pkg/api@1.0.0;
+}
+
+package my:pkg@1.0.0 {
+	interface api {
+		get: func() -> u32;
+	}
+}
+
+package other:pkg@1.0.0 {
+	interface api {}
+}
+`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+	ident := func(s string) Ident {
+		id, err := ParseIdent(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+
+	res := parse()
+	if err := res.RenamePackage(ident("my:pkg"), ident("vendored:my-pkg@1.0.0")); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := res.Package(ident("my:pkg")); ok {
+		t.Error("my:pkg still present after rename")
+	}
+	p, ok := res.Package(ident("vendored:my-pkg@1.0.0"))
+	if !ok {
+		t.Fatal("vendored:my-pkg@1.0.0 not found after rename")
+	}
+	if got, want := interfaceName(p.Interfaces.Get("api")), "vendored:my-pkg/api@1.0.0"; got != want {
+		t.Errorf("interface name: %s, expected %s", got, want)
+	}
+	wit := res.WIT(nil, "")
+	if !strings.Contains(wit, "import vendored:my-pkg/api@1.0.0;") || strings.Contains(wit, "my:pkg") {
+		t.Errorf("WIT not updated after rename:\n%s", wit)
+	}
+
+	errTests := []struct {
+		name     string
+		old, new string
+	}{
+		{"not found", "missing:pkg", "a:b"},
+		{"exists", "my:pkg", "other:pkg@1.0.0"},
+		{"extension", "my:pkg", "a:b/c"},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := parse()
+			if err := res.RenamePackage(ident(tt.old), ident(tt.new)); err == nil {
+				t.Errorf("RenamePackage(%s, %s): expected error", tt.old, tt.new)
+			}
+		})
+	}
+}
+
+func TestResolveAnonymousTypes(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:bar;
+
+interface i {
+	type bytes = list<u8>;
+	record r { a: list<u8>, b: option<bytes>, c: list<u8> }
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var anon []string
+	for _, t := range res.AnonymousTypes() {
+		anon = append(anon, t.Kind.WIT(nil, ""))
+	}
+	var named []string
+	for _, t := range res.NamedTypes() {
+		named = append(named, t.TypeName())
+	}
+	if want := []string{"list<u8>", "option<bytes>", "list<u8>"}; !slices.Equal(anon, want) {
+		t.Errorf("AnonymousTypes(): %v, expected %v", anon, want)
+	}
+	if want := []string{"bytes", "r"}; !slices.Equal(named, want) {
+		t.Errorf("NamedTypes(): %v, expected %v", named, want)
+	}
+	if got, want := len(anon)+len(named), len(res.TypeDefs); got != want {
+		t.Errorf("AnonymousTypes() + NamedTypes(): %d types, expected %d", got, want)
+	}
+}
+
+func TestResolveDuplicateTypes(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:root;
+
+interface a {
+	enum error-code { access, busy }
+	record point { x: u32, y: u32 }
+	resource r;
+	type size = u64;
+	type bytes = list<u8>;
+}
+
+interface b {
+	use a.{point};
+	enum error-code { access, busy }
+	enum other { access, busy, closed }
+	record coord { x: u32, y: u32 }
+	resource r;
+	type len = u64;
+	type count = u64;
+	type data = list<u8>;
+}
+
+package foo:dep@0.1.0 {
+	interface c {
+		enum errno { access, busy }
+		record point { x: u64, y: u64 }
+	}
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, g := range res.DuplicateTypes() {
+		var names []string
+		for _, t := range g {
+			names = append(names, t.QualifiedName())
+		}
+		got = append(got, strings.Join(names, " "))
+	}
+	want := []string{
+		"foo:dep/c@0.1.0#errno foo:root/a#error-code foo:root/b#error-code",
+		"foo:root/a#point foo:root/b#coord",
+		"foo:root/a#bytes foo:root/b#data",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("DuplicateTypes():\n%s\nexpected:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestResolveStripDocs(t *testing.T) {
+	err := loadTestdata(func(path string, res *Resolve) error {
+		t.Run(path, func(t *testing.T) {
+			res.StripDocs()
+			for _, s := range res.SymbolIndex() {
+				if s.Docs != "" {
+					t.Errorf("%s %s: Docs not stripped: %q", s.Kind, s.Name, s.Docs)
+				}
+			}
+			for _, p := range res.Packages {
+				if p.Docs.Contents != "" {
+					t.Errorf("package %s: Docs not stripped: %q", p.Name.String(), p.Docs.Contents)
+				}
+			}
+			if wit := res.WIT(nil, ""); strings.Contains(wit, "///") {
+				t.Errorf("WIT contains docs after StripDocs:\n%s", wit)
+			}
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolveAddInterface(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:bar;
+
+interface i {
+	f: func();
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ptr := func(s string) *string { return &s }
+
+	level := &TypeDef{Kind: &Enum{Cases: []EnumCase{{Name: "info"}, {Name: "error"}}}}
+	var log Interface
+	log.Name = ptr("log")
+	log.TypeDefs.Set("level", level)
+	log.Functions.Set("log", &Function{
+		Name:   "log",
+		Kind:   &Freestanding{},
+		Params: []Param{{Name: "level", Type: level}, {Name: "lines", Type: &TypeDef{Kind: &List{Type: String{}}}}},
+	})
+
+	got, err := res.AddInterface(res.Packages[0].Name, &log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != &log || log.Package != res.Packages[0] {
+		t.Errorf("AddInterface: interface not linked to package %s", res.Packages[0].Name.String())
+	}
+	if level.Owner != &log || level.Name == nil || *level.Name != "level" {
+		t.Errorf("AddInterface: type not linked to interface")
+	}
+	if n := len(res.TypeDefs); n != 2 {
+		t.Errorf("AddInterface: %d types, expected 2", n)
+	}
+	if err := res.Validate(); err != nil {
+		t.Error(err)
+	}
+	want := `package foo:bar;
+
+interface i {
+	f: func();
+}
+
+interface log {
+	enum level { info, error }
+	log: func(level: level, lines: list<string>);
+}
+`
+	if got := res.WIT(nil, ""); got != want {
+		t.Errorf("WIT after AddInterface:\n%s\nexpected:\n%s", got, want)
+	}
+
+	host := Ident{Namespace: "host", Package: "env"}
+	if _, err := res.AddInterface(host, &Interface{Name: ptr("clock")}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := res.Package(host); !ok || len(res.Packages) != 2 {
+		t.Errorf("AddInterface: package %s not created", host.String())
+	}
+
+	errTests := []struct {
+		name  string
+		pkg   Ident
+		iface *Interface
+	}{
+		{"anonymous", host, &Interface{}},
+		{"duplicate", res.Packages[0].Name, &Interface{Name: ptr("log")}},
+		{"invalid package", Ident{Package: "env"}, &Interface{Name: ptr("y")}},
+		{"extension", Ident{Namespace: "host", Package: "env", Extension: "x"}, &Interface{Name: ptr("y")}},
+	}
+	for _, tt := range errTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := res.AddInterface(tt.pkg, tt.iface); err == nil {
+				t.Errorf("AddInterface: expected error")
+			}
+		})
+	}
+}
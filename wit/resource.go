@@ -35,6 +35,12 @@ func (*Resource) hasResource() bool { return true }
 // [handle type]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md#handles
 type Handle interface {
 	TypeDefKind
+
+	// Owned returns true if the handle transfers ownership of the resource,
+	// which is true for [Own] and false for [Borrow]. The recipient of an owned handle
+	// is responsible for dropping it.
+	Owned() bool
+
 	isHandle()
 }
 
@@ -67,6 +73,9 @@ type Own struct {
 	Type *TypeDef
 }
 
+// Owned returns true, as an owned handle transfers ownership of the resource.
+func (*Own) Owned() bool { return true }
+
 func (o *Own) hasResource() bool       { return HasResource(o.Type) }
 func (o *Own) dependsOn(dep Node) bool { return dep == o || DependsOn(o.Type, dep) }
 
@@ -79,6 +88,9 @@ type Borrow struct {
 	Type *TypeDef
 }
 
+// Owned returns false, as a borrowed handle does not transfer ownership of the resource.
+func (*Borrow) Owned() bool { return false }
+
 func (*Borrow) hasBorrow() bool           { return true }
 func (b *Borrow) hasResource() bool       { return HasResource(b.Type) }
 func (b *Borrow) dependsOn(dep Node) bool { return dep == b || DependsOn(b.Type, dep) }
@@ -0,0 +1,52 @@
+package wit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHandleOwned(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:bar;
+
+interface i {
+	resource r;
+	type o = own<r>;
+	type b = borrow<r>;
+	take: func(x: r, y: borrow<r>);
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	i := res.Interfaces[0]
+	take := i.Functions.Get("take")
+	tests := []struct {
+		name  string
+		t     *TypeDef
+		owned bool
+		wit   string
+	}{
+		{"o", i.TypeDefs.Get("o"), true, "type o = own<r>"},
+		{"b", i.TypeDefs.Get("b"), false, "type b = borrow<r>"},
+		{"x", take.Params[0].Type.(*TypeDef), true, "r"},
+		{"y", take.Params[1].Type.(*TypeDef), false, "borrow<r>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, ok := tt.t.Kind.(Handle)
+			if !ok {
+				t.Fatalf("%s: %T, expected Handle", tt.name, tt.t.Kind)
+			}
+			if got := h.Owned(); got != tt.owned {
+				t.Errorf("Owned(): %t, expected %t", got, tt.owned)
+			}
+			var name string
+			if tt.t.Name != nil {
+				name = *tt.t.Name
+			}
+			if got := h.WIT(tt.t, name); got != tt.wit {
+				t.Errorf("WIT(): %q, expected %q", got, tt.wit)
+			}
+		})
+	}
+}
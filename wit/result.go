@@ -12,6 +12,81 @@ type Result struct {
 	Err Type // optional associated [Type] (can be nil)
 }
 
+// ResultShape describes which of the OK and Err types of a [Result] are present.
+type ResultShape int
+
+const (
+	// ResultOKErr describes result<T, E>, with both an OK and an Err type.
+	ResultOKErr ResultShape = iota
+
+	// ResultOKOnly describes result<T>, with an OK type and no Err type.
+	ResultOKOnly
+
+	// ResultErrOnly describes result<_, E>, with an Err type and no OK type.
+	ResultErrOnly
+
+	// ResultEmpty describes a bare result, with neither an OK nor an Err type.
+	ResultEmpty
+)
+
+// String implements [fmt.Stringer], returning the WIT form of the shape,
+// for example "result<_, E>".
+func (s ResultShape) String() string {
+	switch s {
+	case ResultOKErr:
+		return "result<T, E>"
+	case ResultOKOnly:
+		return "result<T>"
+	case ResultErrOnly:
+		return "result<_, E>"
+	case ResultEmpty:
+		return "result"
+	}
+	return "<unknown ResultShape>"
+}
+
+// Shape returns the [ResultShape] of [Result] r.
+// Code generators can use this to select the Go representation of a result,
+// e.g. (T, error) for [ResultOKErr] or error alone for [ResultErrOnly].
+func (r *Result) Shape() ResultShape {
+	switch {
+	case r.OK != nil && r.Err != nil:
+		return ResultOKErr
+	case r.OK != nil:
+		return ResultOKOnly
+	case r.Err != nil:
+		return ResultErrOnly
+	}
+	return ResultEmpty
+}
+
+// ErrorType returns the Err type of [Result] r, or false if r has no Err type.
+// See [Result.ErrorKind] to determine how the error is represented.
+func (r *Result) ErrorType() (Type, bool) {
+	return r.Err, r.Err != nil
+}
+
+// ErrorKind returns the kind of the Err type of [Result] r, with type aliases resolved
+// by [TypeDef.Root], or nil if r has no Err type. For a primitive type, e.g. string,
+// ErrorKind returns the type itself.
+//
+// Code generators can switch on the returned kind to represent the error in Go, for example:
+//   - [*Enum]: a set of error codes, such as wasi:filesystem/types.error-code
+//   - [*Variant]: error codes, some with associated data
+//   - [*Record]: a structured error with named fields
+//   - [*Resource] or a handle: an opaque error object, such as wasi:io/error.error
+//   - [String]: an error message
+func (r *Result) ErrorKind() TypeDefKind {
+	switch t := r.Err.(type) {
+	case nil:
+		return nil
+	case *TypeDef:
+		return t.Root().Kind
+	default:
+		return t
+	}
+}
+
 // Despecialize despecializes [Result] o into a [Variant] with two cases, "ok" and "error".
 // See the [canonical ABI documentation] for more information.
 //
@@ -0,0 +1,54 @@
+package wit
+
+import "testing"
+
+func TestResultShape(t *testing.T) {
+	tests := []struct {
+		r    *Result
+		want ResultShape
+	}{
+		{&Result{OK: U32{}, Err: String{}}, ResultOKErr},
+		{&Result{OK: U32{}, Err: U32{}}, ResultOKErr},
+		{&Result{OK: U32{}}, ResultOKOnly},
+		{&Result{Err: String{}}, ResultErrOnly},
+		{&Result{}, ResultEmpty},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want.String(), func(t *testing.T) {
+			got := tt.r.Shape()
+			if got != tt.want {
+				t.Errorf("Shape(): %v, expected %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResultErrorType(t *testing.T) {
+	name := func(s string) *string { return &s }
+	errorCode := &TypeDef{Name: name("error-code"), Kind: &Enum{Cases: []EnumCase{{Name: "access"}}}}
+	alias := &TypeDef{Name: name("alias"), Kind: errorCode}
+	details := &TypeDef{Name: name("details"), Kind: &Record{Fields: []Field{{Name: "code", Type: U32{}}}}}
+	tests := []struct {
+		name     string
+		r        *Result
+		wantType Type
+		wantKind TypeDefKind
+	}{
+		{"none", &Result{OK: U32{}}, nil, nil},
+		{"string", &Result{Err: String{}}, String{}, String{}},
+		{"enum", &Result{Err: errorCode}, errorCode, errorCode.Kind},
+		{"alias", &Result{OK: U32{}, Err: alias}, alias, errorCode.Kind},
+		{"record", &Result{Err: details}, details, details.Kind},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.r.ErrorType()
+			if got != tt.wantType || ok != (tt.wantType != nil) {
+				t.Errorf("ErrorType(): %v, %t, expected %v", got, ok, tt.wantType)
+			}
+			if got := tt.r.ErrorKind(); got != tt.wantKind {
+				t.Errorf("ErrorKind(): %v, expected %v", got, tt.wantKind)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package wit
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+)
+
+// SearchResult is a match returned by [Resolve.Search].
+type SearchResult struct {
+	// Kind is the kind of the matched item: "world", "interface", "function", or "type".
+	Kind string
+
+	// Name is the qualified name of the matched item, e.g. "wasi:io/streams@0.2.0" for an
+	// interface, or "wasi:io/streams@0.2.0#input-stream" for a type or function in an interface.
+	Name string
+
+	// Node is the matched [*World], [*Interface], [*Function], or [*TypeDef].
+	Node Node
+
+	// Score ranks the match. Higher scores are better matches.
+	Score int
+}
+
+// Search returns the worlds, interfaces, functions, and types in [Resolve] r whose names match query,
+// ordered from best to worst match, then by name. Anonymous interfaces and types are not searched.
+//
+// Matching is case-insensitive, and spaces in query match kebab-case separators,
+// e.g. "input stream" matches "input-stream". From best to worst, query matches:
+//   - the name of an item, e.g. "input-stream"
+//   - the start of the name
+//   - the start of a kebab-case word in the name, e.g. "stream" in "input-stream"
+//   - anywhere in the name
+//   - anywhere in the qualified name, e.g. "io/streams", or the name without
+//     kebab-case separators, e.g. "inputstream"
+//
+// The name of a function is its [Function.BaseName], e.g. "get" for the method [method]fields.get.
+func (r *Resolve) Search(query string) []SearchResult {
+	query = strings.Join(strings.Fields(strings.ToLower(query)), "-")
+	if query == "" {
+		return nil
+	}
+	var results []SearchResult
+	add := func(kind, name, qualified string, node Node) {
+		if score := searchScore(query, strings.ToLower(name), strings.ToLower(qualified)); score > 0 {
+			results = append(results, SearchResult{Kind: kind, Name: qualified, Node: node, Score: score})
+		}
+	}
+	for _, w := range r.Worlds {
+		id := w.Package.Name
+		id.Extension = w.Name
+		qualified := id.String()
+		add("world", w.Name, qualified, w)
+		w.AllItems()(func(name string, item WorldItem) bool {
+			if f, ok := item.(*Function); ok {
+				add("function", f.BaseName(), qualified+"#"+f.Name, f)
+			}
+			return true
+		})
+	}
+	for _, i := range r.Interfaces {
+		if i.Name == nil {
+			continue
+		}
+		qualified := interfaceName(i)
+		add("interface", *i.Name, qualified, i)
+		i.Functions.All()(func(_ string, f *Function) bool {
+			add("function", f.BaseName(), qualified+"#"+f.Name, f)
+			return true
+		})
+	}
+	for _, t := range r.TypeDefs {
+		if t.Name == nil {
+			continue
+		}
+		qualified := t.QualifiedName()
+		if qualified == "" {
+			continue
+		}
+		add("type", *t.Name, qualified, t)
+	}
+	slices.SortStableFunc(results, func(a, b SearchResult) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	return results
+}
+
+// searchScore returns the score of a match of query against name and its qualified name,
+// or 0 if query does not match. All arguments are lowercase.
+func searchScore(query, name, qualified string) int {
+	switch {
+	case name == query:
+		return 5
+	case strings.HasPrefix(name, query):
+		return 4
+	case strings.Contains("-"+name, "-"+query):
+		return 3
+	case strings.Contains(name, query):
+		return 2
+	case strings.Contains(qualified, query),
+		strings.Contains(strings.ReplaceAll(name, "-", ""), strings.ReplaceAll(query, "-", "")):
+		return 1
+	}
+	return 0
+}
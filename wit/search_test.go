@@ -0,0 +1,75 @@
+package wit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveSearch(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package wasi:io@0.2.0;
+
+interface streams {
+	resource input-stream {
+		read: func(len: u64) -> list<u8>;
+	}
+	resource output-stream;
+	type stream-error = u32;
+}
+
+world imports {
+	import streams;
+	import read-all: func();
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	type result struct{ kind, name string }
+	tests := []struct {
+		query string
+		want  []result
+	}{
+		{"", nil},
+		{"nothing", nil},
+		{"Input-Stream", []result{
+			{"type", "wasi:io/streams@0.2.0#input-stream"},
+			{"function", "wasi:io/streams@0.2.0#[method]input-stream.read"},
+		}},
+		{"input stream", []result{
+			{"type", "wasi:io/streams@0.2.0#input-stream"},
+			{"function", "wasi:io/streams@0.2.0#[method]input-stream.read"},
+		}},
+		{"inputstream", []result{{"type", "wasi:io/streams@0.2.0#input-stream"}}},
+		{"stream", []result{
+			{"interface", "wasi:io/streams@0.2.0"},
+			{"type", "wasi:io/streams@0.2.0#stream-error"},
+			{"type", "wasi:io/streams@0.2.0#input-stream"},
+			{"type", "wasi:io/streams@0.2.0#output-stream"},
+			{"function", "wasi:io/streams@0.2.0#[method]input-stream.read"},
+		}},
+		{"read", []result{
+			{"function", "wasi:io/streams@0.2.0#[method]input-stream.read"},
+			{"function", "wasi:io/imports@0.2.0#read-all"},
+		}},
+		{"io/imports", []result{
+			{"world", "wasi:io/imports@0.2.0"},
+			{"function", "wasi:io/imports@0.2.0#read-all"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var got []result
+			for _, r := range res.Search(tt.query) {
+				got = append(got, result{r.Kind, r.Name})
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Search(%q): %v, expected %v", tt.query, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Search(%q)[%d]: %v, expected %v", tt.query, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
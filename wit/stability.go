@@ -1,15 +1,41 @@
 package wit
 
 import (
+	"fmt"
+
 	"github.com/coreos/go-semver/semver"
 )
 
 // Stability represents the version or feature-gated stability of a given feature.
 type Stability interface {
 	Node
+	String() string
 	isStability()
 }
 
+// ParseStability parses one or more WIT feature gates into a [Stability] value,
+// for example: @since(version = 0.2.0) or @unstable(feature = foo).
+// A @since or @unstable gate may be combined with a @deprecated gate,
+// for example: @since(version = 0.2.0) @deprecated(version = 0.2.1).
+// It returns an error if s does not contain exactly one @since or @unstable gate.
+//
+// ParseStability is the inverse of the String method of [Stable] and [Unstable].
+func ParseStability(s string) (Stability, error) {
+	p := &parser{src: s, line: 1}
+	p.next()
+	stability := p.parseGates()
+	if p.err == nil && p.kind != tokenEOF {
+		p.errorf("unexpected %q after feature gate", p.tok)
+	}
+	if p.err != nil {
+		return nil, fmt.Errorf("invalid feature gate %q: %w", s, p.err)
+	}
+	if stability == nil {
+		return nil, fmt.Errorf("invalid feature gate %q: expected @since or @unstable", s)
+	}
+	return stability, nil
+}
+
 // _stability is an embeddable type that conforms to the [Stability] interface.
 type _stability struct{}
 
@@ -17,16 +43,41 @@ func (_stability) isStability() {}
 
 // Stable represents a stable WIT feature, for example: @since(version = 1.2.3)
 //
-// Stable features have an explicit since version and an optional feature name.
+// Stable features have an explicit since version and an optional deprecated version,
+// for example: @deprecated(version = 1.2.4)
 type Stable struct {
 	_stability
 	Since      semver.Version
 	Deprecated *semver.Version
 }
 
-// Unstable represents an unstable WIT feature defined by name.
+// Unstable represents an unstable WIT feature defined by name, for example: @unstable(feature = name)
 type Unstable struct {
 	_stability
 	Feature    string
 	Deprecated *semver.Version
 }
+
+// String returns the WIT text format for [Stable] s, for example: @since(version = 1.2.3).
+// A deprecated version is written as a @deprecated gate on the following line.
+func (s *Stable) String() string {
+	return s.WIT(nil, "")
+}
+
+// String returns the WIT text format for [Unstable] u, for example: @unstable(feature = name).
+// A deprecated version is written as a @deprecated gate on the following line.
+func (u *Unstable) String() string {
+	return u.WIT(nil, "")
+}
+
+// DeprecatedVersion returns the version in which the item with [Stability] s was deprecated,
+// as specified by a @deprecated gate, or nil if s is nil or the item is not deprecated.
+func DeprecatedVersion(s Stability) *semver.Version {
+	switch s := s.(type) {
+	case *Stable:
+		return s.Deprecated
+	case *Unstable:
+		return s.Deprecated
+	}
+	return nil
+}
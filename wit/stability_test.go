@@ -0,0 +1,87 @@
+package wit
+
+import (
+	"testing"
+
+	"github.com/coreos/go-semver/semver"
+)
+
+func TestParseStability(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    Stability
+		wantStr string
+		wantErr bool
+	}{
+		{"@since(version = 0.2.0)", &Stable{Since: *semver.New("0.2.0")}, "", false},
+		{"@since(version = 1.2.3-rc.1)", &Stable{Since: *semver.New("1.2.3-rc.1")}, "", false},
+		{"@unstable(feature = foo)", &Unstable{Feature: "foo"}, "", false},
+		{"@unstable(feature = foo-bar)", &Unstable{Feature: "foo-bar"}, "", false},
+		{
+			"@since(version = 0.2.0)\n@deprecated(version = 0.2.1)",
+			&Stable{Since: *semver.New("0.2.0"), Deprecated: semver.New("0.2.1")}, "", false,
+		},
+		{
+			"@deprecated(version = 0.2.1) @unstable(feature = foo)",
+			&Unstable{Feature: "foo", Deprecated: semver.New("0.2.1")},
+			"@unstable(feature = foo)\n@deprecated(version = 0.2.1)", false,
+		},
+		{"@since(version=0.2.0)", &Stable{Since: *semver.New("0.2.0")}, "@since(version = 0.2.0)", false},
+		{"", nil, "", true},
+		{"@deprecated(version = 0.2.1)", nil, "", true},
+		{"@since(version = 0.2)", nil, "", true},
+		{"@since(feature = foo)", nil, "", true},
+		{"@unstable(version = 0.2.0)", nil, "", true},
+		{"@stable(version = 0.2.0)", nil, "", true},
+		{"@since(version = 0.2.0) func", nil, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := ParseStability(tt.s)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseStability(%q): expected error, got %v", tt.s, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseStability(%q): %v", tt.s, err)
+			}
+			want := tt.wantStr
+			if want == "" {
+				want = tt.s
+			}
+			if s := got.String(); s != want {
+				t.Errorf("String(): %q, expected %q", s, want)
+			}
+			if got, want := got.WIT(nil, ""), tt.want.WIT(nil, ""); got != want {
+				t.Errorf("ParseStability(%q): %q, expected %q", tt.s, got, want)
+			}
+		})
+	}
+}
+
+func TestDeprecatedVersion(t *testing.T) {
+	tests := []struct {
+		name string
+		s    Stability
+		want string
+	}{
+		{"nil", nil, ""},
+		{"stable", &Stable{Since: *semver.New("0.2.0")}, ""},
+		{"unstable", &Unstable{Feature: "foo"}, ""},
+		{"deprecated stable", &Stable{Since: *semver.New("0.2.0"), Deprecated: semver.New("0.2.1")}, "0.2.1"},
+		{"deprecated unstable", &Unstable{Feature: "foo", Deprecated: semver.New("0.3.0")}, "0.3.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			if v := DeprecatedVersion(tt.s); v != nil {
+				got = v.String()
+			}
+			if got != tt.want {
+				t.Errorf("DeprecatedVersion(): %q, expected %q", got, tt.want)
+			}
+		})
+	}
+}
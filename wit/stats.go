@@ -0,0 +1,111 @@
+package wit
+
+// Stats contains counts of the items in a [Resolve], as returned by [Resolve.Stats].
+type Stats struct {
+	Packages   int
+	Worlds     int
+	Interfaces int
+	Functions  int // freestanding functions, constructors, methods, and static functions
+	Resources  int
+
+	// Kinds counts each [TypeDef] by kind, as reported by WITKind, e.g. "record" or "variant".
+	// Type aliases, including named aliases of primitive types, are counted as "type".
+	Kinds map[string]int
+
+	// MaxDepth is the maximum nesting depth of any type, e.g. 2 for list<option<u8>>.
+	// Primitive types have depth 0. A type alias has the depth of the type it refers to.
+	// A handle has depth 1, regardless of its resource.
+	MaxDepth int
+}
+
+// Stats returns counts of the packages, worlds, interfaces, functions, and types in [Resolve] r.
+func (r *Resolve) Stats() Stats {
+	s := Stats{
+		Packages:   len(r.Packages),
+		Worlds:     len(r.Worlds),
+		Interfaces: len(r.Interfaces),
+		Kinds:      make(map[string]int),
+	}
+	r.AllFunctions()(func(*Function) bool {
+		s.Functions++
+		return true
+	})
+	depths := make(map[*TypeDef]int)
+	for _, t := range r.TypeDefs {
+		switch {
+		case t.IsAlias():
+			s.Kinds["type"]++
+		case t.Kind != nil:
+			s.Kinds[t.Kind.WITKind()]++
+		}
+		if _, ok := t.Kind.(*Resource); ok {
+			s.Resources++
+		}
+		s.MaxDepth = max(s.MaxDepth, typeDepth(t, depths))
+	}
+	return s
+}
+
+// typeDepth returns the nesting depth of t, memoized in depths.
+// A type that refers to itself contributes depth 0 for the recursive reference.
+func typeDepth(t Type, depths map[*TypeDef]int) int {
+	td, ok := t.(*TypeDef)
+	if !ok || td == nil {
+		return 0
+	}
+	if d, ok := depths[td]; ok {
+		return d
+	}
+	depths[td] = 0 // guard against cycles
+	var d int
+	switch k := td.Kind.(type) {
+	case *TypeDef:
+		d = typeDepth(k, depths)
+	case Handle:
+		d = 1
+	default:
+		for _, child := range kindTypes(td.Kind) {
+			d = max(d, typeDepth(child, depths))
+		}
+		if _, ok := td.Kind.(Type); !ok {
+			d++
+		}
+	}
+	depths[td] = d
+	return d
+}
+
+// UsedPrimitives returns the set of [Primitive] types that appear in [Resolve] r,
+// keyed by WIT name, e.g. "u32" or "string". It includes primitive types used in the
+// parameters and results of each [Function], and in the definition of each [TypeDef].
+// Primitive types used only in the [Canonical ABI] representation of a type, such as the
+// discriminant of a variant or the pointer and length of a list, are not included.
+//
+// [Canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md
+func (r *Resolve) UsedPrimitives() map[string]bool {
+	used := make(map[string]bool)
+	add := func(t Type) {
+		if p, ok := t.(Primitive); ok {
+			used[p.WITKind()] = true
+		}
+	}
+	for _, t := range r.TypeDefs {
+		if p, ok := t.Kind.(Primitive); ok {
+			add(p)
+			continue
+		}
+		for _, child := range kindTypes(t.Kind) {
+			add(child)
+		}
+	}
+	r.AllFunctions()(func(f *Function) bool {
+		for _, p := range f.Params {
+			add(p.Type)
+		}
+		for _, p := range f.Results {
+			add(p.Type)
+		}
+		return true
+	})
+	return used
+}
@@ -0,0 +1,100 @@
+package wit
+
+import (
+	"maps"
+	"strings"
+	"testing"
+)
+
+func TestResolveStats(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:bar;
+
+interface i {
+	resource r {
+		constructor();
+		get: func() -> u32;
+	}
+	type id = u32;
+	type r2 = r;
+	record point { x: u32, y: u32 }
+	enum color { red, green }
+	f: func(p: list<option<point>>) -> result<id>;
+}
+
+world w {
+	import i;
+	import g: func();
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := res.Stats()
+	want := Stats{
+		Packages:   1,
+		Worlds:     1,
+		Interfaces: 1,
+		Functions:  4,
+		Resources:  1,
+		Kinds: map[string]int{
+			"resource": 1,
+			"type":     2,
+			"record":   1,
+			"enum":     1,
+			"borrow":   1, // self parameter of get
+			"own":      1, // constructor result
+			"list":     1,
+			"option":   1,
+			"result":   1,
+		},
+		MaxDepth: 3, // list<option<point>>
+	}
+	if got.Packages != want.Packages || got.Worlds != want.Worlds || got.Interfaces != want.Interfaces ||
+		got.Functions != want.Functions || got.Resources != want.Resources || got.MaxDepth != want.MaxDepth {
+		t.Errorf("Stats(): %+v, expected %+v", got, want)
+	}
+	if !maps.Equal(got.Kinds, want.Kinds) {
+		t.Errorf("Stats().Kinds: %v, expected %v", got.Kinds, want.Kinds)
+	}
+}
+
+func TestTypeDepthCycle(t *testing.T) {
+	a := &TypeDef{}
+	a.Kind = &List{Type: a}
+	if got := typeDepth(a, make(map[*TypeDef]int)); got != 1 {
+		t.Errorf("typeDepth: %d, expected 1", got)
+	}
+}
+
+func TestResolveUsedPrimitives(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:bar;
+
+interface i {
+	type id = u64;
+	record point { x: s32, y: s32 }
+	variant v { a(list<u8>), b }
+	f: func(p: point, c: char) -> result<id, string>;
+}
+
+world w {
+	import i;
+	import g: func(b: bool) -> f64;
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := res.UsedPrimitives()
+	want := map[string]bool{
+		"u64":    true,
+		"s32":    true,
+		"u8":     true,
+		"char":   true,
+		"string": true,
+		"bool":   true,
+		"f64":    true,
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("UsedPrimitives(): got %v, want %v", got, want)
+	}
+}
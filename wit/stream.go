@@ -11,6 +11,12 @@ type Stream struct {
 	End     Type // optional associated Type (can be nil)
 }
 
+// HasEnd returns true if [Stream] s has an associated End [Type], as in stream<T, E>,
+// or false for stream<T> or a bare stream.
+func (s *Stream) HasEnd() bool {
+	return s.End != nil
+}
+
 // Size returns the [ABI byte size] for a [Stream].
 // TODO: what is the ABI size of a stream?
 //
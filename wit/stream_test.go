@@ -0,0 +1,26 @@
+package wit
+
+import "testing"
+
+func TestStream(t *testing.T) {
+	tests := []struct {
+		s   *Stream
+		end bool
+		wit string
+	}{
+		{&Stream{}, false, "stream"},
+		{&Stream{Element: U8{}}, false, "stream<u8>"},
+		{&Stream{Element: U8{}, End: String{}}, true, "stream<u8, string>"},
+		{&Stream{End: String{}}, true, "stream<_, string>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.wit, func(t *testing.T) {
+			if got := tt.s.HasEnd(); got != tt.end {
+				t.Errorf("HasEnd(): %t, expected %t", got, tt.end)
+			}
+			if got := tt.s.WIT(nil, ""); got != tt.wit {
+				t.Errorf("WIT(): %q, expected %q", got, tt.wit)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package wit
+
+// Symbol describes a named entity in a [Resolve], as returned by [Resolve.SymbolIndex].
+// It can be serialized to JSON, for example to feed autocompletion in an editor.
+type Symbol struct {
+	// Kind is the kind of the symbol: "world", "interface", "function", "type",
+	// "field", "case", or "flag". Enum and variant cases both have kind "case".
+	Kind string `json:"kind"`
+
+	// Name is the qualified name of the symbol, e.g. "wasi:io/streams@0.2.0" for an interface,
+	// "wasi:io/streams@0.2.0#input-stream" for a type or function, or
+	// "wasi:io/streams@0.2.0#stream-error.closed" for a field, case, or flag.
+	Name string `json:"name"`
+
+	// Container is the qualified name of the symbol that contains this symbol,
+	// or empty for worlds and interfaces.
+	Container string `json:"container,omitempty"`
+
+	// Docs is the documentation of the symbol, if any.
+	Docs string `json:"docs,omitempty"`
+
+	// Node is the [*World], [*Interface], [*Function], [*TypeDef], [*Field], [*Case],
+	// [*EnumCase], or [*Flag] the symbol describes.
+	Node Node `json:"-"`
+}
+
+// SymbolIndex returns a [Symbol] for every named world, interface, function, and type in
+// [Resolve] r, and for the fields, cases, and flags of each named type.
+// Anonymous interfaces and types, and types imported into a world with use statements, are not included.
+//
+// Source positions are not recorded by [DecodeJSON] or [ParseWIT], so symbols are
+// listed in declaration order: by package, then interfaces followed by worlds, each followed
+// by its types and their members, then its functions.
+func (r *Resolve) SymbolIndex() []Symbol {
+	var syms []Symbol
+	add := func(kind, name, container string, docs *Docs, node Node) {
+		syms = append(syms, Symbol{Kind: kind, Name: name, Container: container, Docs: docs.Contents, Node: node})
+	}
+	addType := func(t *TypeDef, container string) {
+		if alias, ok := t.Kind.(*TypeDef); ok && alias.Owner != t.Owner {
+			return // imported with a use statement
+		}
+		name := container + "#" + *t.Name
+		add("type", name, container, &t.Docs, t)
+		switch kind := t.Kind.(type) {
+		case *Record:
+			for i := range kind.Fields {
+				f := &kind.Fields[i]
+				add("field", name+"."+f.Name, name, &f.Docs, f)
+			}
+		case *Variant:
+			for i := range kind.Cases {
+				c := &kind.Cases[i]
+				add("case", name+"."+c.Name, name, &c.Docs, c)
+			}
+		case *Enum:
+			for i := range kind.Cases {
+				c := &kind.Cases[i]
+				add("case", name+"."+c.Name, name, &c.Docs, c)
+			}
+		case *Flags:
+			for i := range kind.Flags {
+				f := &kind.Flags[i]
+				add("flag", name+"."+f.Name, name, &f.Docs, f)
+			}
+		}
+	}
+	addFunction := func(f *Function, container string) {
+		add("function", container+"#"+f.Name, container, &f.Docs, f)
+	}
+
+	for _, p := range r.Packages {
+		p.Interfaces.All()(func(_ string, i *Interface) bool {
+			name := interfaceName(i)
+			add("interface", name, "", &i.Docs, i)
+			i.TypeDefs.All()(func(_ string, t *TypeDef) bool {
+				addType(t, name)
+				return true
+			})
+			i.Functions.All()(func(_ string, f *Function) bool {
+				addFunction(f, name)
+				return true
+			})
+			return true
+		})
+		p.Worlds.All()(func(_ string, w *World) bool {
+			id := w.Package.Name
+			id.Extension = w.Name
+			name := id.String()
+			add("world", name, "", &w.Docs, w)
+			w.Imports.All()(func(_ string, item WorldItem) bool {
+				if t, ok := item.(*TypeDef); ok && t.Owner == w {
+					addType(t, name)
+				}
+				return true
+			})
+			w.AllItems()(func(_ string, item WorldItem) bool {
+				if f, ok := item.(*Function); ok {
+					addFunction(f, name)
+				}
+				return true
+			})
+			return true
+		})
+	}
+	return syms
+}
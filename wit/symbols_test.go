@@ -0,0 +1,82 @@
+package wit
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestResolveSymbolIndex(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:bar@0.1.0;
+
+/// Streams.
+interface streams {
+	/// An error.
+	variant stream-error {
+		/// Closed.
+		closed,
+		failed(u32),
+	}
+	resource input-stream {
+		read: func(len: u64) -> list<u8>;
+	}
+}
+
+interface types {
+	use streams.{input-stream};
+	record r { a: u32 }
+	type alias = r;
+	enum e { x }
+	flags f { y }
+	open: func() -> input-stream;
+}
+
+world w {
+	import streams;
+	use streams.{stream-error};
+	record point { x: s32 }
+	export run: func();
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	syms := res.SymbolIndex()
+	var got []string
+	for _, s := range syms {
+		got = append(got, s.Kind+" "+s.Name)
+	}
+	want := []string{
+		"interface foo:bar/streams@0.1.0",
+		"type foo:bar/streams@0.1.0#stream-error",
+		"case foo:bar/streams@0.1.0#stream-error.closed",
+		"case foo:bar/streams@0.1.0#stream-error.failed",
+		"type foo:bar/streams@0.1.0#input-stream",
+		"function foo:bar/streams@0.1.0#[method]input-stream.read",
+		"interface foo:bar/types@0.1.0",
+		"type foo:bar/types@0.1.0#r",
+		"field foo:bar/types@0.1.0#r.a",
+		"type foo:bar/types@0.1.0#alias",
+		"type foo:bar/types@0.1.0#e",
+		"case foo:bar/types@0.1.0#e.x",
+		"type foo:bar/types@0.1.0#f",
+		"flag foo:bar/types@0.1.0#f.y",
+		"function foo:bar/types@0.1.0#open",
+		"world foo:bar/w@0.1.0",
+		"type foo:bar/w@0.1.0#point",
+		"field foo:bar/w@0.1.0#point.x",
+		"function foo:bar/w@0.1.0#run",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("SymbolIndex():\n%s\nexpected:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	data, err := json.Marshal(syms[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), `{"kind":"case","name":"foo:bar/streams@0.1.0#stream-error.closed","container":"foo:bar/streams@0.1.0#stream-error","docs":"Closed."}`; got != want {
+		t.Errorf("json.Marshal: %s, expected %s", got, want)
+	}
+}
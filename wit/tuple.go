@@ -30,6 +30,20 @@ func (t *Tuple) Type() Type {
 	return typ
 }
 
+// IsHomogeneous returns true if all types in [Tuple] t are the same,
+// in which case t could be represented as an array, e.g. [2]uint32 for tuple<u32, u32>.
+// A tuple with a single type is homogeneous. An empty tuple is not, as it has no element type.
+func (t *Tuple) IsHomogeneous() bool {
+	return t.Type() != nil
+}
+
+// ElementType returns the type of every element in [Tuple] t, if t is homogeneous.
+// It returns false if t is empty or contains more than one type. See [Tuple.IsHomogeneous].
+func (t *Tuple) ElementType() (Type, bool) {
+	typ := t.Type()
+	return typ, typ != nil
+}
+
 // Despecialize despecializes [Tuple] e into a [Record] with 0-based integer field names.
 // See the [canonical ABI documentation] for more information.
 //
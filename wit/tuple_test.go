@@ -0,0 +1,31 @@
+package wit
+
+import "testing"
+
+func TestTupleElementType(t *testing.T) {
+	str := &TypeDef{Kind: &List{Type: U8{}}}
+	tests := []struct {
+		name  string
+		types []Type
+		want  Type
+	}{
+		{"empty", nil, nil},
+		{"single", []Type{U32{}}, U32{}},
+		{"homogeneous", []Type{U32{}, U32{}, U32{}}, U32{}},
+		{"homogeneous typedef", []Type{str, str}, str},
+		{"heterogeneous", []Type{U32{}, U64{}}, nil},
+		{"distinct typedefs", []Type{str, &TypeDef{Kind: &List{Type: U8{}}}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tup := &Tuple{Types: tt.types}
+			got, ok := tup.ElementType()
+			if got != tt.want || ok != (tt.want != nil) {
+				t.Errorf("ElementType(): %v, %t, expected %v", got, ok, tt.want)
+			}
+			if got := tup.IsHomogeneous(); got != (tt.want != nil) {
+				t.Errorf("IsHomogeneous(): %t, expected %t", got, tt.want != nil)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package wit
 
 import (
 	"fmt"
+	"strings"
 	"unsafe"
 
 	"go.bytecodealliance.org/wit/iterate"
@@ -40,7 +41,10 @@ func (_type) isType()          {}
 
 // ParseType parses a WIT [primitive type] string into
 // the associated Type implementation from this package.
-// It returns an error if the type string is not recognized.
+// It returns an error wrapping [ErrUnknownType] if the type string is not recognized.
+//
+// Primitive types are zero-sized, so the returned Type does not allocate:
+// every value of a given primitive type shares the same underlying instance.
 //
 // [primitive type]: https://component-model.bytecodealliance.org/design/wit.html#primitive-types
 func ParseType(s string) (Type, error) {
@@ -72,7 +76,62 @@ func ParseType(s string) (Type, error) {
 	case "string":
 		return String{}, nil
 	}
-	return nil, fmt.Errorf("unknown primitive type %q", s)
+	return nil, fmt.Errorf("%w: unknown primitive type %q", ErrUnknownType, s)
+}
+
+// ParseTypeRef parses s into a [Type] defined in [Resolve] r, or a primitive type as parsed by [ParseType].
+// A type defined in an interface or world is referred to by its qualified name, with the type name
+// following a "." or "#", for example: wasi:io/streams.input-stream, wasi:io/streams@0.2.0.input-stream,
+// or wasi:clocks/wall-clock@0.2.0#datetime, as returned by [TypeDef.QualifiedName].
+// A reference without a version matches any version of the package, but only if exactly one version is in r.
+//
+// It returns an error wrapping [ErrUnknownType] if the package, interface or world, or type is not found in r.
+func ParseTypeRef(r *Resolve, s string) (Type, error) {
+	if t, err := ParseType(s); err == nil {
+		return t, nil
+	}
+	path, name, ok := cutTypeRef(s)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q is not a primitive type or qualified type name", ErrUnknownType, s)
+	}
+	id, err := ParseIdent(path)
+	if err != nil {
+		return nil, err
+	}
+	if id.Extension == "" {
+		return nil, fmt.Errorf("%w: %q has no interface or world name", ErrUnknownType, s)
+	}
+	p, ok := r.Package(id)
+	if !ok {
+		id.Extension = ""
+		return nil, fmt.Errorf("%w: package %s not found", ErrUnknownType, id.String())
+	}
+	if i, ok := p.Interfaces.GetOK(id.Extension); ok {
+		if t, ok := i.TypeDefs.GetOK(name); ok {
+			return t, nil
+		}
+		return nil, fmt.Errorf("%w: type %s not found in interface %s", ErrUnknownType, name, interfaceName(i))
+	}
+	if w, ok := p.Worlds.GetOK(id.Extension); ok {
+		if t, ok := w.Imports.Get(name).(*TypeDef); ok {
+			return t, nil
+		}
+		return nil, fmt.Errorf("%w: type %s not found in world %s", ErrUnknownType, name, worldName(w))
+	}
+	return nil, fmt.Errorf("%w: interface or world %s not found in package %s", ErrUnknownType, id.Extension, p.Name.String())
+}
+
+// cutTypeRef splits a qualified type name into its interface or world path and type name.
+// The type name follows a "#" or the last ".", and must start with a letter.
+func cutTypeRef(s string) (path, name string, ok bool) {
+	if path, name, ok := strings.Cut(s, "#"); ok {
+		return path, name, name != ""
+	}
+	i := strings.LastIndexByte(s, '.')
+	if i < 0 || i+1 >= len(s) || !isLetter(s[i+1]) {
+		return "", "", false
+	}
+	return s[:i], s[i+1:], true
 }
 
 // primitive is a type constraint of the Go equivalents of WIT [primitive types].
@@ -256,6 +315,16 @@ type F64 struct{ _primitive[float64] }
 // [rune]: https://pkg.go.dev/builtin#rune
 type Char struct{ _primitive[char] }
 
+// IsValidChar returns true if r is a valid value of the WIT type [Char]: a [Unicode scalar value]
+// in the range [0, 0x10FFFF], excluding the surrogate code points [0xD800, 0xDFFF].
+// The [Canonical ABI] traps when lifting an invalid char.
+//
+// [Unicode scalar value]: https://unicode.org/glossary/#unicode_scalar_value
+// [Canonical ABI]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/CanonicalABI.md#loading
+func IsValidChar(r rune) bool {
+	return (r >= 0 && r < 0xD800) || (r > 0xDFFF && r <= 0x10FFFF)
+}
+
 // String represents the WIT [primitive type] string, a finite string of Unicode characters.
 // It is equivalent to the Go type [string].
 // It implements the [Node], [ABI], [Type], and [TypeDefKind] interfaces.
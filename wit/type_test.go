@@ -0,0 +1,135 @@
+package wit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestIsValidChar(t *testing.T) {
+	tests := []struct {
+		r    rune
+		want bool
+	}{
+		{0, true},
+		{'a', true},
+		{0xD7FF, true},
+		{0xD800, false},
+		{0xDBFF, false},
+		{0xDC00, false},
+		{0xDFFF, false},
+		{0xE000, true},
+		{utf8.RuneError, true},
+		{0x10FFFF, true},
+		{0x110000, false},
+		{-1, false},
+	}
+	for _, tt := range tests {
+		if got := IsValidChar(tt.r); got != tt.want {
+			t.Errorf("IsValidChar(%#x): %t, expected %t", tt.r, got, tt.want)
+		}
+		if got := utf8.ValidRune(tt.r); got != tt.want {
+			t.Errorf("utf8.ValidRune(%#x): %t, expected %t", tt.r, got, tt.want)
+		}
+	}
+}
+
+var primitiveTypeNames = []string{
+	"bool", "s8", "u8", "s16", "u16", "s32", "u32", "s64", "u64", "f32", "f64", "char", "string",
+}
+
+func TestParseTypeAllocs(t *testing.T) {
+	allocs := testing.AllocsPerRun(100, func() {
+		for _, s := range primitiveTypeNames {
+			if _, err := ParseType(s); err != nil {
+				t.Fatal(err)
+			}
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("ParseType: %v allocations, expected 0", allocs)
+	}
+}
+
+func BenchmarkParseType(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, s := range primitiveTypeNames {
+			if _, err := ParseType(s); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestParseTypeRef(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package wasi:io@0.2.0 {
+	interface streams {
+		resource input-stream;
+	}
+	world w {
+		type t = u32;
+	}
+}
+
+package wasi:clocks@0.2.0 {
+	interface wall-clock {
+		record datetime { seconds: u64, nanoseconds: u32 }
+	}
+}
+
+package wasi:clocks@0.3.0 {
+	interface wall-clock {
+		record datetime { seconds: u64, nanoseconds: u32 }
+	}
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		s       string
+		want    string // qualified name, or WIT for primitives
+		wantErr bool
+	}{
+		{"u32", "u32", false},
+		{"wasi:io/streams.input-stream", "wasi:io/streams@0.2.0#input-stream", false},
+		{"wasi:io/streams@0.2.0.input-stream", "wasi:io/streams@0.2.0#input-stream", false},
+		{"wasi:io/streams@0.2.0#input-stream", "wasi:io/streams@0.2.0#input-stream", false},
+		{"wasi:io/w.t", "wasi:io/w@0.2.0#t", false},
+		{"wasi:clocks/wall-clock@0.3.0.datetime", "wasi:clocks/wall-clock@0.3.0#datetime", false},
+		{"wasi:clocks/wall-clock.datetime", "", true}, // ambiguous version
+		{"wasi:io/streams.output-stream", "", true},
+		{"wasi:io/poll.pollable", "", true},
+		{"wasi:http/types.request", "", true},
+		{"wasi:io.input-stream", "", true},
+		{"wasi:io/streams@0.2.0", "", true},
+		{"input-stream", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := ParseTypeRef(res, tt.s)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParseTypeRef(%q): expected error, got %v", tt.s, got)
+				} else if !errors.Is(err, ErrUnknownType) && !errors.Is(err, ErrInvalidPackageName) {
+					t.Errorf("ParseTypeRef(%q): %v, expected ErrUnknownType or ErrInvalidPackageName", tt.s, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTypeRef(%q): %v", tt.s, err)
+			}
+			var name string
+			if td, ok := got.(*TypeDef); ok {
+				name = td.QualifiedName()
+			} else {
+				name = got.WIT(nil, "")
+			}
+			if name != tt.want {
+				t.Errorf("ParseTypeRef(%q): %s, expected %s", tt.s, name, tt.want)
+			}
+		})
+	}
+}
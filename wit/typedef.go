@@ -28,6 +28,48 @@ func (t *TypeDef) TypeName() string {
 	return ""
 }
 
+// QualifiedName returns the fully qualified name of [TypeDef] t, consisting of
+// the [Ident] of its owning [Interface] or [World] and its type name,
+// e.g. "wasi:clocks/wall-clock@0.2.0#datetime".
+// It returns an empty string if t is anonymous or belongs to an anonymous (inline) interface.
+// If t has no owner, it returns the type name.
+func (t *TypeDef) QualifiedName() string {
+	if t.Name == nil {
+		return ""
+	}
+	var id Ident
+	switch owner := t.Owner.(type) {
+	case *Interface:
+		if owner.Name == nil || owner.Package == nil {
+			return ""
+		}
+		id = owner.Package.Name
+		id.Extension = *owner.Name
+	case *World:
+		if owner.Package == nil {
+			return ""
+		}
+		id = owner.Package.Name
+		id.Extension = owner.Name
+	default:
+		return *t.Name
+	}
+	return id.String() + "#" + *t.Name
+}
+
+// IsAlias returns true if [TypeDef] t is a [type alias]: its Kind is a reference to another
+// [TypeDef] or a primitive [Type], as declared by "type a = b", "type a = u32", or "use i.{b as a}".
+// Use [TypeDef.Root] to follow a chain of aliases to the underlying definition.
+//
+// A type declared with a compound type, such as "type a = list<u32>", is not an alias,
+// as it is the only named definition of its type.
+//
+// [type alias]: https://component-model.bytecodealliance.org/design/wit.html#type-aliases
+func (t *TypeDef) IsAlias() bool {
+	_, ok := t.Kind.(Type)
+	return ok
+}
+
 // TypeDef returns the parent [TypeDef] of [TypeDef] t.
 // If t is not a [type alias], TypeDef returns t.
 //
@@ -54,6 +96,52 @@ func (t *TypeDef) Root() *TypeDef {
 	}
 }
 
+// TypeDocs contains the documentation for a [TypeDef] and its members, as returned by [TypeDef.AllDocs].
+type TypeDocs struct {
+	// Docs is the documentation for the type itself.
+	Docs Docs
+
+	// Members maps the name of each field, case, or flag of the type to its documentation.
+	// Members without documentation are omitted.
+	Members map[string]Docs
+}
+
+// AllDocs returns the documentation for [TypeDef] t, along with the documentation for
+// each [Field] of a [Record], [Case] of a [Variant], [EnumCase] of an [Enum], or [Flag] of [Flags].
+// If t is an alias, the members are those of the type it refers to, as returned by [TypeDef.Root].
+// Members is nil if no member has documentation.
+func (t *TypeDef) AllDocs() TypeDocs {
+	docs := TypeDocs{Docs: t.Docs}
+	add := func(name string, d Docs) {
+		if d.Contents == "" {
+			return
+		}
+		if docs.Members == nil {
+			docs.Members = make(map[string]Docs)
+		}
+		docs.Members[name] = d
+	}
+	switch k := t.Root().Kind.(type) {
+	case *Record:
+		for _, f := range k.Fields {
+			add(f.Name, f.Docs)
+		}
+	case *Variant:
+		for _, c := range k.Cases {
+			add(c.Name, c.Docs)
+		}
+	case *Enum:
+		for _, c := range k.Cases {
+			add(c.Name, c.Docs)
+		}
+	case *Flags:
+		for _, f := range k.Flags {
+			add(f.Name, f.Docs)
+		}
+	}
+	return docs
+}
+
 // Constructor returns the constructor for [TypeDef] t, or nil if none.
 // Currently t must be a [Resource] to have a constructor.
 func (t *TypeDef) Constructor() *Function {
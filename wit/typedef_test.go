@@ -0,0 +1,170 @@
+package wit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTypeDefQualifiedName(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package wasi:clocks@0.2.0;
+
+interface wall-clock {
+	record datetime { seconds: u64, nanoseconds: u32 }
+	now: func() -> datetime;
+}
+
+world w {
+	type t = u32;
+	import inline: interface {
+		type i = u32;
+	}
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]string{
+		"datetime": "wasi:clocks/wall-clock@0.2.0#datetime",
+		"t":        "wasi:clocks/w@0.2.0#t",
+		"i":        "",
+	}
+	for _, td := range res.TypeDefs {
+		name := td.TypeName()
+		want, ok := tests[name]
+		if !ok {
+			continue
+		}
+		delete(tests, name)
+		if got := td.QualifiedName(); got != want {
+			t.Errorf("(*TypeDef).QualifiedName() for %q: %q, expected %q", name, got, want)
+		}
+	}
+	for name := range tests {
+		t.Errorf("type %q not found", name)
+	}
+
+	name := "x"
+	if got, want := (&TypeDef{Name: &name}).QualifiedName(), "x"; got != want {
+		t.Errorf("QualifiedName() without owner: %q, expected %q", got, want)
+	}
+	if got := (&TypeDef{Kind: &List{Type: U8{}}}).QualifiedName(); got != "" {
+		t.Errorf("QualifiedName() for anonymous type: %q, expected empty string", got)
+	}
+}
+
+func TestTypeDefIsAlias(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:bar;
+
+interface a {
+	record r { x: u32 }
+	type r1 = r;
+	type r2 = r1;
+	type n = u32;
+	type l = list<u32>;
+}
+
+interface b {
+	use a.{r2 as r3};
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := res.Packages[0].Interfaces.Get("a")
+	b := res.Packages[0].Interfaces.Get("b")
+	r := a.TypeDefs.Get("r")
+	tests := []struct {
+		t     *TypeDef
+		alias bool
+		root  *TypeDef
+	}{
+		{r, false, r},
+		{a.TypeDefs.Get("r1"), true, r},
+		{a.TypeDefs.Get("r2"), true, r},
+		{a.TypeDefs.Get("n"), true, a.TypeDefs.Get("n")},
+		{a.TypeDefs.Get("l"), false, a.TypeDefs.Get("l")},
+		{b.TypeDefs.Get("r3"), true, r},
+	}
+	for _, tt := range tests {
+		if got := tt.t.IsAlias(); got != tt.alias {
+			t.Errorf("(*TypeDef).IsAlias() for %s: %t, expected %t", tt.t.TypeName(), got, tt.alias)
+		}
+		if got := tt.t.Root(); got != tt.root {
+			t.Errorf("(*TypeDef).Root() for %s: %s, expected %s", tt.t.TypeName(), got.TypeName(), tt.root.TypeName())
+		}
+	}
+}
+
+func TestTypeDefAllDocs(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:bar;
+
+interface i {
+	/// A record.
+	record r {
+		/// Field a.
+		a: u32,
+		b: u32,
+	}
+
+	/// A variant.
+	variant v {
+		/// Case a.
+		a(u32),
+		/// Case b.
+		b,
+	}
+
+	enum e {
+		/// Case x.
+		x,
+		y,
+	}
+
+	flags f {
+		/// Flag x.
+		x,
+	}
+
+	/// An alias.
+	type alias = r;
+
+	/// A list.
+	type l = list<u8>;
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		docs    string
+		members map[string]string
+	}{
+		{"r", "A record.", map[string]string{"a": "Field a."}},
+		{"v", "A variant.", map[string]string{"a": "Case a.", "b": "Case b."}},
+		{"e", "", map[string]string{"x": "Case x."}},
+		{"f", "", map[string]string{"x": "Flag x."}},
+		{"alias", "An alias.", map[string]string{"a": "Field a."}},
+		{"l", "A list.", nil},
+	}
+	i := res.Interfaces[0]
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs := i.TypeDefs.Get(tt.name).AllDocs()
+			if docs.Docs.Contents != tt.docs {
+				t.Errorf("Docs: %q, expected %q", docs.Docs.Contents, tt.docs)
+			}
+			if tt.members == nil && docs.Members != nil {
+				t.Errorf("Members: %v, expected nil", docs.Members)
+			}
+			if len(docs.Members) != len(tt.members) {
+				t.Errorf("Members: %v, expected %v", docs.Members, tt.members)
+			}
+			for name, want := range tt.members {
+				if got := docs.Members[name].Contents; got != want {
+					t.Errorf("Members[%q]: %q, expected %q", name, got, want)
+				}
+			}
+		})
+	}
+}
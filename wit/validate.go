@@ -0,0 +1,86 @@
+package wit
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Validate checks [Resolve] r for structural errors that [DecodeJSON] does not detect,
+// such as might be present in malformed JSON. It returns the first error found, or nil.
+//
+// Validate reports an error if:
+//   - an [Own] or [Borrow] handle does not refer, through any aliases, to a [Resource]
+//   - a [Variant] or [Enum] has no cases, as it has no valid values
+//
+// Other degenerate types are valid: an empty [Record] (see [Record.IsEmpty]) has a size of 0,
+// and a [Result] may have neither an OK nor an Err type (see [Result.Shape]).
+func (r *Resolve) Validate() error {
+	for i, t := range r.TypeDefs {
+		if err := validateTypeDef(t); err != nil {
+			return fmt.Errorf("%s: %w", typeDefDescription(i, t), err)
+		}
+	}
+	return nil
+}
+
+func validateTypeDef(t *TypeDef) error {
+	switch k := t.Kind.(type) {
+	case *Own:
+		return validateHandle(k, k.Type)
+	case *Borrow:
+		return validateHandle(k, k.Type)
+	case *Variant:
+		if len(k.Cases) == 0 {
+			return errors.New("variant has no cases")
+		}
+	case *Enum:
+		if len(k.Cases) == 0 {
+			return errors.New("enum has no cases")
+		}
+	}
+	return nil
+}
+
+// validateHandle returns an error if the target of [Handle] h is not a [Resource].
+func validateHandle(h Handle, target *TypeDef) error {
+	if target == nil {
+		return fmt.Errorf("%s handle has no type", h.WITKind())
+	}
+	// Follow aliases, guarding against cycles
+	seen := make(map[*TypeDef]bool)
+	root := target
+	for {
+		alias, ok := root.Kind.(*TypeDef)
+		if !ok {
+			break
+		}
+		if seen[root] {
+			return fmt.Errorf("%s handle refers to a cyclic type alias", h.WITKind())
+		}
+		seen[root] = true
+		root = alias
+	}
+	if _, ok := root.Kind.(*Resource); !ok {
+		return fmt.Errorf("%s handle refers to %s, not a resource", h.WITKind(), typeDefDescription(-1, root))
+	}
+	return nil
+}
+
+// typeDefDescription returns a description of [TypeDef] t for use in error messages,
+// including its index i in [Resolve.TypeDefs] if i >= 0.
+func typeDefDescription(i int, t *TypeDef) string {
+	var s string
+	if name := t.QualifiedName(); name != "" {
+		s = "type " + name
+	} else {
+		s = "anonymous type"
+	}
+	if t.Kind != nil {
+		s += " (" + t.Kind.WITKind() + ")"
+	}
+	if i >= 0 {
+		s += " at index " + strconv.Itoa(i)
+	}
+	return s
+}
@@ -0,0 +1,102 @@
+package wit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateTestdata(t *testing.T) {
+	err := loadTestdata(func(path string, res *Resolve) error {
+		t.Run(path, func(t *testing.T) {
+			if err := res.Validate(); err != nil {
+				t.Error(err)
+			}
+		})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidateHandles(t *testing.T) {
+	name := func(s string) *string { return &s }
+	resource := &TypeDef{Name: name("r"), Kind: &Resource{}}
+	alias := &TypeDef{Name: name("a"), Kind: resource}
+	record := &TypeDef{Name: name("rec"), Kind: &Record{}}
+	cyclic := &TypeDef{Name: name("c")}
+	cyclic.Kind = cyclic
+
+	tests := []struct {
+		name    string
+		t       *TypeDef
+		wantErr string
+	}{
+		{"own resource", &TypeDef{Kind: &Own{Type: resource}}, ""},
+		{"borrow resource", &TypeDef{Kind: &Borrow{Type: resource}}, ""},
+		{"own alias", &TypeDef{Kind: &Own{Type: alias}}, ""},
+		{"own record", &TypeDef{Kind: &Own{Type: record}}, "own handle refers to type rec (record), not a resource"},
+		{"borrow record", &TypeDef{Kind: &Borrow{Type: record}}, "borrow handle refers to type rec (record), not a resource"},
+		{"own nil", &TypeDef{Kind: &Own{}}, "own handle has no type"},
+		{"own cycle", &TypeDef{Kind: &Own{Type: cyclic}}, "cyclic type alias"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := &Resolve{TypeDefs: []*TypeDef{resource, alias, record, tt.t}}
+			err := res.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate(): %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate(): %v, expected error containing %q", err, tt.wantErr)
+			}
+			if err != nil && !strings.Contains(err.Error(), "at index 3") {
+				t.Errorf("Validate(): %v, expected error to identify the handle at index 3", err)
+			}
+		})
+	}
+}
+
+func TestValidateDegenerateTypes(t *testing.T) {
+	tests := []struct {
+		name    string
+		kind    TypeDefKind
+		wantErr string
+		size    uintptr
+		align   uintptr
+	}{
+		{"empty record", &Record{}, "", 0, 1},
+		{"empty tuple", &Tuple{}, "", 0, 1},
+		{"bare result", &Result{}, "", 1, 1},
+		{"option of empty record", &Option{Type: &TypeDef{Kind: &Record{}}}, "", 1, 1},
+		{"empty variant", &Variant{}, "variant has no cases", 0, 0},
+		{"empty enum", &Enum{}, "enum has no cases", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			td := &TypeDef{Kind: tt.kind}
+			err := (&Resolve{TypeDefs: []*TypeDef{td}}).Validate()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Validate(): %v, expected error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Validate(): %v", err)
+			}
+			if got := td.Size(); got != tt.size {
+				t.Errorf("Size(): %d, expected %d", got, tt.size)
+			}
+			if got := td.Align(); got != tt.align {
+				t.Errorf("Align(): %d, expected %d", got, tt.align)
+			}
+		})
+	}
+	if !(&Record{}).IsEmpty() || (&Record{Fields: []Field{{Name: "a", Type: U8{}}}}).IsEmpty() {
+		t.Errorf("IsEmpty(): incorrect result")
+	}
+}
@@ -15,8 +15,7 @@ type Variant struct {
 // This will only succeed if v has no associated types. If v has
 // associated types, then it will return nil.
 func (v *Variant) Enum() *Enum {
-	types := v.Types()
-	if len(types) > 0 {
+	if v.HasPayload() {
 		return nil
 	}
 	e := &Enum{
@@ -29,6 +28,44 @@ func (v *Variant) Enum() *Enum {
 	return e
 }
 
+// IsEnumLike returns true if no case in [Variant] v has an associated type,
+// in which case v is semantically equivalent to an [Enum].
+func (v *Variant) IsEnumLike() bool {
+	return !v.HasPayload()
+}
+
+// AsOption returns the associated type of the some case if [Variant] v is
+// semantically equivalent to an [Option], e.g. variant { none, some(T) }.
+// It returns false unless v has exactly two cases, in order: a case named none
+// with no associated type, and a case named some with an associated type.
+// A variant of this shape has the same Canonical ABI representation as option<T>.
+func (v *Variant) AsOption() (Type, bool) {
+	if len(v.Cases) != 2 {
+		return nil, false
+	}
+	none, some := &v.Cases[0], &v.Cases[1]
+	if none.Name != "none" || none.Type != nil || some.Name != "some" || some.Type == nil {
+		return nil, false
+	}
+	return some.Type, true
+}
+
+// HasPayload returns true if at least one case in [Variant] v has an associated type.
+func (v *Variant) HasPayload() bool {
+	for i := range v.Cases {
+		if v.Cases[i].Type != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Discriminant returns the WIT integer type (u8, u16, or u32) of the
+// discriminant that identifies the case of [Variant] v in the Canonical ABI.
+func (v *Variant) Discriminant() Type {
+	return Discriminant(len(v.Cases))
+}
+
 // Types returns the unique associated types in [Variant] v.
 func (v *Variant) Types() []Type {
 	var types []Type
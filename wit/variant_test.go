@@ -0,0 +1,64 @@
+package wit
+
+import "testing"
+
+func TestVariantIsEnumLike(t *testing.T) {
+	tests := []struct {
+		name         string
+		v            *Variant
+		enumLike     bool
+		discriminant Type
+	}{
+		{"no payloads", &Variant{Cases: []Case{{Name: "a"}, {Name: "b"}}}, true, U8{}},
+		{"one payload", &Variant{Cases: []Case{{Name: "a"}, {Name: "b", Type: String{}}}}, false, U8{}},
+		{"256 cases", &Variant{Cases: make([]Case, 256)}, true, U8{}},
+		{"257 cases", &Variant{Cases: make([]Case, 257)}, true, U16{}},
+		{"65537 cases", &Variant{Cases: make([]Case, 65537)}, true, U32{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.v.IsEnumLike(); got != tt.enumLike {
+				t.Errorf("IsEnumLike(): %t, expected %t", got, tt.enumLike)
+			}
+			if got := tt.v.HasPayload(); got == tt.enumLike {
+				t.Errorf("HasPayload(): %t, expected %t", got, !tt.enumLike)
+			}
+			if got := tt.v.Enum() != nil; got != tt.enumLike {
+				t.Errorf("Enum() != nil: %t, expected %t", got, tt.enumLike)
+			}
+			if got := tt.v.Discriminant(); got != tt.discriminant {
+				t.Errorf("Discriminant(): %s, expected %s", got.WIT(nil, ""), tt.discriminant.WIT(nil, ""))
+			}
+		})
+	}
+}
+
+func TestVariantAsOption(t *testing.T) {
+	tests := []struct {
+		name string
+		v    *Variant
+		want Type
+	}{
+		{"none some", &Variant{Cases: []Case{{Name: "none"}, {Name: "some", Type: String{}}}}, String{}},
+		{"some none", &Variant{Cases: []Case{{Name: "some", Type: String{}}, {Name: "none"}}}, nil},
+		{"some without payload", &Variant{Cases: []Case{{Name: "none"}, {Name: "some"}}}, nil},
+		{"none with payload", &Variant{Cases: []Case{{Name: "none", Type: U8{}}, {Name: "some", Type: String{}}}}, nil},
+		{"other names", &Variant{Cases: []Case{{Name: "empty"}, {Name: "value", Type: String{}}}}, nil},
+		{"three cases", &Variant{Cases: []Case{{Name: "none"}, {Name: "some", Type: String{}}, {Name: "other"}}}, nil},
+		{"one case", &Variant{Cases: []Case{{Name: "some", Type: String{}}}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.v.AsOption()
+			if ok != (tt.want != nil) || got != tt.want {
+				t.Errorf("AsOption(): %v, %t, expected %v", got, ok, tt.want)
+			}
+			if ok {
+				o := &Option{Type: got}
+				if o.Size() != tt.v.Size() || o.Align() != tt.v.Align() {
+					t.Errorf("Option size/align %d/%d, expected %d/%d", o.Size(), o.Align(), tt.v.Size(), tt.v.Align())
+				}
+			}
+		})
+	}
+}
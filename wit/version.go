@@ -0,0 +1,114 @@
+package wit
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/coreos/go-semver/semver"
+)
+
+// Satisfies returns true if the version of [Ident] id satisfies constraint.
+// A constraint is a space- or comma-separated list of comparisons, all of which must match.
+// Each comparison is a [SemVer] version, optionally preceded by an operator:
+//
+//   - "=", or no operator: exactly equal to version
+//   - "!=": not equal to version
+//   - ">", ">=", "<", "<=": greater or less than version
+//   - "^": compatible with version, e.g. "^1.2" matches >=1.2.0 <2.0.0 and "^0.2" matches >=0.2.0 <0.3.0
+//   - "~": patch-level changes to version, e.g. "~1.2" matches >=1.2.0 <1.3.0
+//
+// Versions may omit the minor or patch components, e.g. "1" or "1.2", which are treated as 0.
+// An empty constraint or "*" is unconstrained and matches any id, including one without a version.
+// An Ident without a version only satisfies an unconstrained request.
+// Satisfies returns false if constraint is malformed.
+//
+// [SemVer]: https://semver.org/
+func (id *Ident) Satisfies(constraint string) bool {
+	fields := strings.FieldsFunc(constraint, func(c rune) bool { return c == ' ' || c == ',' })
+	if len(fields) == 0 || (len(fields) == 1 && fields[0] == "*") {
+		return true
+	}
+	if id.Version == nil {
+		return false
+	}
+	for _, f := range fields {
+		ok, err := satisfies(id.Version, f)
+		if err != nil || !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// satisfies returns true if v satisfies comparison c, such as ">=1.2.0" or "^0.2".
+func satisfies(v *semver.Version, c string) (bool, error) {
+	op, s := cutOperator(c)
+	min, n, err := parsePartialVersion(s)
+	if err != nil {
+		return false, err
+	}
+	cmp := v.Compare(*min)
+	switch op {
+	case "", "=":
+		return cmp == 0, nil
+	case "!=":
+		return cmp != 0, nil
+	case ">":
+		return cmp > 0, nil
+	case ">=":
+		return cmp >= 0, nil
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	}
+
+	// Range operators: v must be >= min and < max.
+	max := semver.Version{Major: min.Major, Minor: min.Minor, Patch: min.Patch}
+	switch {
+	case op == "~" && n == 1:
+		max.BumpMajor()
+	case op == "~":
+		max.BumpMinor()
+	case min.Major != 0 || n == 1:
+		max.BumpMajor()
+	case min.Minor != 0 || n == 2:
+		max.BumpMinor()
+	default:
+		max.BumpPatch()
+	}
+	return cmp >= 0 && v.LessThan(max), nil
+}
+
+// cutOperator splits comparison c into an operator and version string.
+func cutOperator(c string) (op, version string) {
+	for _, op := range []string{">=", "<=", "!=", ">", "<", "=", "^", "~"} {
+		if after, found := strings.CutPrefix(c, op); found {
+			return op, after
+		}
+	}
+	return "", c
+}
+
+// parsePartialVersion parses a full or partial [SemVer] version string, such as "1", "1.2", or "1.2.3-rc.1".
+// Missing minor or patch components are set to 0. It returns the number of components present in s.
+func parsePartialVersion(s string) (*semver.Version, int, error) {
+	n := strings.Count(s, ".") + 1
+	if n >= 3 || strings.ContainsAny(s, "-+") {
+		v, err := semver.NewVersion(s)
+		return v, 3, err
+	}
+	var v semver.Version
+	for i, part := range strings.SplitN(s, ".", 2) {
+		x, err := strconv.ParseUint(part, 10, 63)
+		if err != nil {
+			return nil, 0, err
+		}
+		if i == 0 {
+			v.Major = int64(x)
+		} else {
+			v.Minor = int64(x)
+		}
+	}
+	return &v, n, nil
+}
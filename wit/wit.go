@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"slices"
 	"strings"
+
+	"go.bytecodealliance.org/wit/iterate"
+	"go.bytecodealliance.org/wit/ordered"
 )
 
 // Node is the common interface implemented by the WIT ([WebAssembly Interface Type])
@@ -61,9 +64,36 @@ func Filter(w *World, i *Interface) Node {
 	}
 }
 
+// Order returns a [Node] suitable for passing to (Resolve).WIT or [Resolve.WriteWITDir]
+// to emit items in the order given by names, for example the [Symbol] names returned by
+// [Resolve.SymbolIndex] for a reference WIT file, to minimize diffs when regenerating it.
+// If ctx was returned by [Filter], the returned Node also applies the filter.
+//
+// Interfaces and worlds are named as in "wasi:io/streams@0.2.0", and the types, functions,
+// and world imports and exports they contain as in "wasi:io/streams@0.2.0#input-stream".
+// Names may omit the package version. Within each package, interface, or world, items
+// named in names are written first, in the order given, followed by unlisted items in
+// their existing order. The WIT text format always lists the interfaces in a package
+// before its worlds, and use statements in an interface before its type definitions.
+// The [Resolve] is not modified.
+func Order(ctx Node, names []string) Node {
+	f := &witFilter{}
+	if filter, ok := ctx.(*witFilter); ok && filter != nil {
+		*f = *filter
+	}
+	f.rank = make(map[string]int, len(names))
+	for i, name := range names {
+		if _, ok := f.rank[name]; !ok {
+			f.rank[name] = i
+		}
+	}
+	return f
+}
+
 type witFilter struct {
-	w *World
-	i *Interface
+	w    *World
+	i    *Interface
+	rank map[string]int // from Order
 }
 
 func (*witFilter) WITKind() string             { panic("BUG: WITKind called on filter") }
@@ -77,6 +107,51 @@ func (f *witFilter) filter(node Node) bool {
 	return (f.w != nil && filterNode(f.w, node)) || (f.i != nil && filterNode(f.i, node))
 }
 
+// orderedAll returns a [sequence] that yields the entries in m in the order specified by [Order],
+// or the order of m if f is nil or has no order. The name of each entry is the string
+// form of owner, followed by sep and its key if sep is non-empty.
+//
+// [sequence]: https://github.com/golang/go/issues/61897
+func orderedAll[V any](f *witFilter, m *ordered.Map[string, V], owner Ident, sep string) iterate.Seq2[string, V] {
+	if f == nil || len(f.rank) == 0 {
+		return m.All()
+	}
+	rankOf := func(k string) int {
+		id := owner
+		for {
+			if sep == "" {
+				id.Extension = k
+			}
+			name := id.String()
+			if sep != "" {
+				name += sep + k
+			}
+			if r, ok := f.rank[name]; ok {
+				return r
+			}
+			if id.Version == nil {
+				return len(f.rank)
+			}
+			id.Version = nil
+		}
+	}
+	var keys []string
+	ranks := make(map[string]int, m.Len())
+	m.All()(func(k string, _ V) bool {
+		keys = append(keys, k)
+		ranks[k] = rankOf(k)
+		return true
+	})
+	slices.SortStableFunc(keys, func(a, b string) int { return ranks[a] - ranks[b] })
+	return func(yield func(string, V) bool) {
+		for _, k := range keys {
+			if !yield(k, m.Get(k)) {
+				return
+			}
+		}
+	}
+}
+
 func filterNode(target, node Node) bool {
 	switch node := node.(type) {
 	case *Package:
@@ -263,7 +338,12 @@ func (w *World) WIT(ctx Node, name string) string {
 	b.WriteString(escape(name)) // TODO: compare to w.Name?
 	b.WriteString(" {")
 	n := 0
-	w.Imports.All()(func(name string, i WorldItem) bool {
+	var id Ident
+	if w.Package != nil {
+		id = w.Package.Name
+		id.Extension = w.Name
+	}
+	orderedAll(filter, &w.Imports, id, "#")(func(name string, i WorldItem) bool {
 		if filter.filter(i) {
 			return true
 		}
@@ -280,7 +360,7 @@ func (w *World) WIT(ctx Node, name string) string {
 		n++
 		return true
 	})
-	w.Exports.All()(func(name string, i WorldItem) bool {
+	orderedAll(filter, &w.Exports, id, "#")(func(name string, i WorldItem) bool {
 		if filter.filter(i) {
 			return true
 		}
@@ -322,6 +402,14 @@ func (*Interface) WITKind() string { return "interface" }
 // [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
 func (i *Interface) WIT(ctx Node, name string) string {
 	// filter, _ := ctx.(*witFilter)
+	order, _ := ctx.(*witFilter)
+	var id Ident
+	if i.Name != nil && i.Package != nil {
+		id = i.Package.Name
+		id.Extension = *i.Name
+	} else {
+		order = nil // Inline interfaces keep their existing order
+	}
 
 	if i.Name != nil && name == "" {
 		name = *i.Name
@@ -369,7 +457,7 @@ func (i *Interface) WIT(ctx Node, name string) string {
 	n := 0
 
 	// Emit use statements first
-	i.TypeDefs.All()(func(name string, td *TypeDef) bool {
+	orderedAll(order, &i.TypeDefs, id, "#")(func(name string, td *TypeDef) bool {
 		// if filter.filter(td) {
 		// 	return true
 		// }
@@ -386,7 +474,7 @@ func (i *Interface) WIT(ctx Node, name string) string {
 	})
 
 	// Declarations
-	i.TypeDefs.All()(func(name string, td *TypeDef) bool {
+	orderedAll(order, &i.TypeDefs, id, "#")(func(name string, td *TypeDef) bool {
 		// if filter.filter(td) {
 		// 	return true
 		// }
@@ -403,7 +491,7 @@ func (i *Interface) WIT(ctx Node, name string) string {
 	})
 
 	// Functions
-	i.Functions.All()(func(name string, f *Function) bool {
+	orderedAll(order, &i.Functions, id, "#")(func(name string, f *Function) bool {
 		// if filter.filter(f) {
 		// 	return true
 		// }
@@ -655,6 +743,8 @@ func (r *Resource) WIT(ctx Node, name string) string {
 func (*Own) WITKind() string { return "own" }
 
 // WIT returns the [WIT] text format for [Own] h.
+// An anonymous owned handle is written as the name of its resource, e.g. "r",
+// which is equivalent to own<r>. A named handle is written as "type name = own<r>".
 //
 // [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
 func (o *Own) WIT(ctx Node, name string) string {
@@ -931,6 +1021,19 @@ func (f *Future) WIT(_ Node, name string) string {
 	return b.String()
 }
 
+// WITKind returns the WIT kind.
+func (*ErrorContext) WITKind() string { return "error-context" }
+
+// WIT returns the [WIT] text format for [ErrorContext] e.
+//
+// [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
+func (e *ErrorContext) WIT(_ Node, name string) string {
+	if name != "" {
+		return "type " + escape(name) + " = error-context"
+	}
+	return "error-context"
+}
+
 // WITKind returns the WIT kind.
 func (*Stream) WITKind() string { return "stream" }
 
@@ -1131,7 +1234,7 @@ func (p *Package) WIT(ctx Node, name string) string {
 		b.WriteString(";\n")
 	}
 	i := 0
-	p.Interfaces.All()(func(name string, face *Interface) bool {
+	orderedAll(filter, &p.Interfaces, p.Name, "")(func(name string, face *Interface) bool {
 		if filter.filter(face) {
 			return true
 		}
@@ -1145,7 +1248,7 @@ func (p *Package) WIT(ctx Node, name string) string {
 		i++
 		return true
 	})
-	p.Worlds.All()(func(name string, w *World) bool {
+	orderedAll(filter, &p.Worlds, p.Name, "")(func(name string, w *World) bool {
 		if filter.filter(w) {
 			return true
 		}
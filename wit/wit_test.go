@@ -0,0 +1,77 @@
+package wit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOrder(t *testing.T) {
+	const input = `package foo:bar@1.0.0;
+
+interface a {
+	record x { v: u32 }
+	record y { v: u32 }
+	f: func();
+	g: func();
+	h: func();
+}
+
+interface b {}
+
+world w {
+	import a;
+	import b;
+	export run: func();
+}
+`
+	res, err := ParseWIT(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := res.WIT(nil, "")
+	order := Order(nil, []string{
+		"foo:bar/w",
+		"foo:bar/b@1.0.0",
+		"foo:bar/a#y",
+		"foo:bar/a@1.0.0#h",
+		"foo:bar/w#foo:bar/b@1.0.0",
+		"foo:bar/missing",
+	})
+	// Interfaces are always written before worlds. Unlisted items keep their existing order.
+	want := `package foo:bar@1.0.0;
+
+interface b {}
+
+interface a {
+	record y { v: u32 }
+	record x { v: u32 }
+	h: func();
+	f: func();
+	g: func();
+}
+
+world w {
+	import b;
+	import a;
+	export run: func();
+}
+`
+	if got := res.WIT(order, ""); got != want {
+		t.Errorf("WIT(Order(...)):\n%s\nexpected:\n%s", got, want)
+	}
+	if got := res.WIT(nil, ""); got != before {
+		t.Errorf("WIT(nil) after WIT(Order(...)):\n%s\nexpected:\n%s", got, before)
+	}
+
+	// Order applies a filter passed as ctx.
+	var b *Interface
+	for _, i := range res.Interfaces {
+		if i.Match("b") {
+			b = i
+		}
+	}
+	got := res.WIT(Order(Filter(nil, b), []string{"foo:bar/b"}), "")
+	if strings.Contains(got, "interface a") {
+		t.Errorf("WIT(Order(Filter(...))) contains filtered interface a:\n%s", got)
+	}
+}
@@ -0,0 +1,53 @@
+package wit
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// WriteWITDir writes the [WIT] text format of [Resolve] r to directory dir, one package per file,
+// in the layout expected by wasm-tools and wit-deps. The main package, the one that no other
+// package depends on, is written to dir/<package>.wit, e.g. dir/cli.wit for wasi:cli.
+// Each other package is written to dir/deps/<namespace>-<package>[@<version>]/<package>.wit,
+// e.g. dir/deps/wasi-io@0.2.0/io.wit for wasi:io@0.2.0.
+// References to types in other packages are written as fully qualified use statements.
+// Other packages with no interfaces or worlds are omitted. The main package is always written.
+// Directories are created as needed, and existing files are overwritten.
+// As with [Resolve.WIT], ctx may be nil, or a [Node] returned by [Filter] or [Order].
+//
+// [WIT]: https://github.com/WebAssembly/component-model/blob/main/design/mvp/WIT.md
+func (r *Resolve) WriteWITDir(ctx Node, dir string) error {
+	// Sort packages topologically by dependency, the same as [Resolve.WIT]
+	packages := slices.Clone(r.Packages)
+	slices.SortFunc(packages, comparePackages)
+	slices.Reverse(packages)
+
+	for i, p := range packages {
+		if i > 0 && p.Interfaces.Len() == 0 && p.Worlds.Len() == 0 {
+			continue
+		}
+		wit := p.WIT(ctx, "")
+		path := filepath.Join(dir, p.Name.Package+".wit")
+		if i > 0 {
+			path = filepath.Join(dir, "deps", packageDirName(p.Name), p.Name.Package+".wit")
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(wit), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// packageDirName returns the name of the deps directory for package id,
+// e.g. "wasi-io@0.2.0" for wasi:io@0.2.0.
+func packageDirName(id Ident) string {
+	name := id.Namespace + "-" + id.Package
+	if id.Version != nil {
+		name += "@" + id.Version.String()
+	}
+	return name
+}
@@ -0,0 +1,74 @@
+package wit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveWriteWITDir(t *testing.T) {
+	res, err := LoadJSON(filepath.Join(testdataPath, "wasi/cli-command.wit.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := res.WriteWITDir(nil, dir); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path string
+		pkg  string
+	}{
+		{"cli.wit", "package wasi:cli@0.2.0;"},
+		{"deps/wasi-io@0.2.0/io.wit", "package wasi:io@0.2.0;"},
+		{"deps/wasi-clocks@0.2.0/clocks.wit", "package wasi:clocks@0.2.0;"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join(dir, tt.path))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(data), tt.pkg+"\n") {
+				t.Errorf("%s does not declare %s", tt.path, tt.pkg)
+			}
+		})
+	}
+
+	// The main package refers to types in its deps by qualified name.
+	data, err := os.ReadFile(filepath.Join(dir, "cli.wit"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "use wasi:io/streams@0.2.0.{") {
+		t.Errorf("cli.wit does not use wasi:io/streams@0.2.0:\n%s", data)
+	}
+}
+
+func TestResolveWriteWITDirFilter(t *testing.T) {
+	res, err := LoadJSON(filepath.Join(testdataPath, "wasi/cli-command.wit.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var streams *Interface
+	for _, i := range res.Interfaces {
+		if i.Match("wasi:io/streams") {
+			streams = i
+		}
+	}
+	if streams == nil {
+		t.Fatal("wasi:io/streams not found")
+	}
+
+	// The main package is written even if the filter excludes all of its items.
+	dir := t.TempDir()
+	if err := res.WriteWITDir(Filter(nil, streams), dir); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"cli.wit", "deps/wasi-io@0.2.0/io.wit"} {
+		if _, err := os.Stat(filepath.Join(dir, path)); err != nil {
+			t.Error(err)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package wit
 
 import (
+	"slices"
+
+	"github.com/coreos/go-semver/semver"
 	"go.bytecodealliance.org/wit/iterate"
 	"go.bytecodealliance.org/wit/ordered"
 )
@@ -13,11 +16,41 @@ type World struct {
 	_typeOwner
 
 	Name      string
-	Imports   ordered.Map[string, WorldItem]
-	Exports   ordered.Map[string, WorldItem]
-	Package   *Package  // the Package this World belongs to (must be non-nil)
-	Stability Stability // WIT @since or @unstable (nil if unknown)
+	Imports   ordered.Map[string, WorldItem] // in declaration order
+	Exports   ordered.Map[string, WorldItem] // in declaration order
+	Package   *Package                       // the Package this World belongs to (must be non-nil)
+	Stability Stability                      // WIT @since or @unstable (nil if unknown)
 	Docs      Docs
+
+	// Uses and Includes record the use and include statements in the WIT source of the world,
+	// in declaration order. They are populated by [ParseWIT], but not by [DecodeJSON],
+	// because the JSON format produced by wasm-tools does not record them.
+	// The types and items they contribute are also present in Imports and Exports.
+	Uses     []WorldUse
+	Includes []WorldInclude
+}
+
+// WorldUse represents a use statement in a [World],
+// e.g. use wasi:io/streams@0.2.0.{input-stream, output-stream as out};
+type WorldUse struct {
+	// Interface is the interface types are used from.
+	Interface *Interface
+
+	// Types are the type aliases declared in the world by the use statement, in order.
+	// The name of each type is its name in the world, and its Kind is the used type,
+	// which has the original name.
+	Types []*TypeDef
+}
+
+// WorldInclude represents an include statement in a [World],
+// e.g. include wasi:cli/imports@0.2.0 with { environment as env };
+type WorldInclude struct {
+	// World is the included world.
+	World *World
+
+	// With maps the name of each renamed import or export in World to its name
+	// in the including world. It is nil if no items are renamed.
+	With map[string]string
 }
 
 // Clone returns a shallow clone of w.
@@ -25,6 +58,8 @@ func (w *World) Clone() *World {
 	c := *w
 	c.Imports = *w.Imports.Clone()
 	c.Exports = *w.Exports.Clone()
+	c.Uses = slices.Clone(w.Uses)
+	c.Includes = slices.Clone(w.Includes)
 	return &c
 }
 
@@ -122,6 +157,265 @@ func (w *World) AllItems() iterate.Seq2[string, WorldItem] {
 	}
 }
 
+// NamedWorldItem is a [WorldItem] and the name it is imported or exported as in a [World].
+type NamedWorldItem struct {
+	Name string
+	Item WorldItem
+}
+
+// SortedImports returns the imports of [World] w in a stable order: the order they were
+// declared in WIT, which is preserved by [DecodeJSON] and [ParseWIT].
+func (w *World) SortedImports() []NamedWorldItem {
+	return namedWorldItems(&w.Imports)
+}
+
+// SortedExports returns the exports of [World] w in a stable order: the order they were
+// declared in WIT, which is preserved by [DecodeJSON] and [ParseWIT].
+func (w *World) SortedExports() []NamedWorldItem {
+	return namedWorldItems(&w.Exports)
+}
+
+// NamedInterface is an [Interface] and the name it is imported or exported as in a [World].
+// The name is the key of the interface in the world's Imports or Exports, which is the qualified
+// name of a named interface, e.g. "wasi:io/streams@0.2.0", or the local name of an inline interface.
+type NamedInterface struct {
+	Name      string
+	Interface *Interface
+	Stability Stability // WIT @since or @unstable of the import or export (nil if unknown)
+}
+
+// ImportedInterfaces returns the interfaces imported by [World] w, in declaration order.
+// See [World.ExportedInterfaces].
+func (w *World) ImportedInterfaces() []*NamedInterface {
+	return namedInterfaces(&w.Imports)
+}
+
+// ExportedInterfaces returns the interfaces exported by [World] w, in declaration order.
+// See [World.ImportedInterfaces].
+func (w *World) ExportedInterfaces() []*NamedInterface {
+	return namedInterfaces(&w.Exports)
+}
+
+func namedInterfaces(m *ordered.Map[string, WorldItem]) []*NamedInterface {
+	var faces []*NamedInterface
+	m.All()(func(name string, item WorldItem) bool {
+		if ref, ok := item.(*InterfaceRef); ok {
+			faces = append(faces, &NamedInterface{Name: name, Interface: ref.Interface, Stability: ref.Stability})
+		}
+		return true
+	})
+	return faces
+}
+
+func namedWorldItems(m *ordered.Map[string, WorldItem]) []NamedWorldItem {
+	items := make([]NamedWorldItem, 0, m.Len())
+	m.All()(func(name string, item WorldItem) bool {
+		items = append(items, NamedWorldItem{Name: name, Item: item})
+		return true
+	})
+	return items
+}
+
+// ImportedTypes returns the types imported into [World] w, in declaration order.
+// Interface and function imports are not included.
+//
+// Each type is a [TypeDef] owned by w. A type imported with a use statement,
+// e.g. use wasi:io/streams.{input-stream}, is an alias whose Kind is the
+// [TypeDef] it refers to. Call [TypeDef.Root] to find the original definition
+// in the interface that declared it, rather than redefining the type.
+func (w *World) ImportedTypes() []*TypeDef {
+	var types []*TypeDef
+	w.Imports.All()(func(_ string, item WorldItem) bool {
+		if t, ok := item.(*TypeDef); ok {
+			types = append(types, t)
+		}
+		return true
+	})
+	return types
+}
+
+// ImportedResources returns the resource types imported into [World] w, in declaration order.
+// See [World.ExportedResources].
+func (w *World) ImportedResources() []*TypeDef {
+	return w.resources(&w.Imports)
+}
+
+// ExportedResources returns the resource types exported from [World] w, in declaration order.
+// This includes resources defined in the interfaces exported from w, and resource types
+// exported directly by w. Type aliases are resolved to the original resource with [TypeDef.Root],
+// and each resource appears once. A resource used by an exported interface but defined in an
+// imported interface is not exported; it is returned by [World.ImportedResources].
+func (w *World) ExportedResources() []*TypeDef {
+	return w.resources(&w.Exports)
+}
+
+// PrimaryExport returns the interface exported by [World] w, if w exports exactly one interface.
+// Exported functions and types are not counted. It returns false if w exports no interfaces,
+// or more than one, rather than choosing one arbitrarily.
+// The interface may be anonymous, if it was declared inline in w.
+func (w *World) PrimaryExport() (*Interface, bool) {
+	var primary *Interface
+	var n int
+	w.Exports.All()(func(_ string, item WorldItem) bool {
+		if ref, ok := item.(*InterfaceRef); ok {
+			primary = ref.Interface
+			n++
+		}
+		return n < 2
+	})
+	if n != 1 {
+		return nil, false
+	}
+	return primary, true
+}
+
+// ResourceTable describes a per-instance table of handles to resources of a single type,
+// as returned by [World.ResourceTables].
+type ResourceTable struct {
+	// Resource is the resource type, resolved to its original definition with [TypeDef.Root].
+	Resource *TypeDef
+
+	// Owner is the [Interface] or [World] that defines Resource.
+	Owner TypeOwner
+
+	// Direction is [Imported] if Resource is implemented by the host and imported into the
+	// component, or [Exported] if Resource is implemented by the component.
+	Direction Direction
+}
+
+// ResourceTables returns the resource handle tables needed to instantiate a component
+// that targets [World] w, one for each distinct resource type and [Direction].
+// Tables for imported resources are listed first, each in declaration order.
+//
+// A resource is exported if it is defined in an interface exported by w, or exported by w directly.
+// A resource used by an exported interface but defined in an imported interface is imported.
+// A host generator can allocate a map from rep to handle for each table.
+func (w *World) ResourceTables() []ResourceTable {
+	var tables []ResourceTable
+	for _, t := range w.ImportedResources() {
+		tables = append(tables, ResourceTable{Resource: t, Owner: t.Owner, Direction: Imported})
+	}
+	for _, t := range w.ExportedResources() {
+		tables = append(tables, ResourceTable{Resource: t, Owner: t.Owner, Direction: Exported})
+	}
+	return tables
+}
+
+// resources returns the resources in items, which are the imports or exports of [World] w.
+// A resource is included if it is defined by w or by an interface in items.
+func (w *World) resources(items *ordered.Map[string, WorldItem]) []*TypeDef {
+	var resources []*TypeDef
+	owned := func(owner TypeOwner) bool {
+		if owner == w {
+			return true
+		}
+		found := false
+		items.All()(func(_ string, item WorldItem) bool {
+			ref, ok := item.(*InterfaceRef)
+			found = ok && ref.Interface == owner
+			return !found
+		})
+		return found
+	}
+	add := func(t *TypeDef) {
+		root := t.Root()
+		if _, ok := root.Kind.(*Resource); ok && owned(root.Owner) && !slices.Contains(resources, root) {
+			resources = append(resources, root)
+		}
+	}
+	items.All()(func(_ string, item WorldItem) bool {
+		switch item := item.(type) {
+		case *InterfaceRef:
+			item.Interface.TypeDefs.All()(func(_ string, t *TypeDef) bool {
+				add(t)
+				return true
+			})
+		case *TypeDef:
+			add(item)
+		}
+		return true
+	})
+	return resources
+}
+
+// A Conflict describes a function name used by more than one freestanding function
+// imported into or exported from a [World], which would collide if the functions were
+// generated into a single namespace, such as a flat Go package.
+type Conflict struct {
+	// Name is the function name shared by each owner.
+	Name string
+
+	// Owners lists each function named Name and its owner,
+	// in the order they appear in the World's imports, then exports.
+	Owners []ConflictOwner
+}
+
+// ConflictOwner is a function and its owner in a [Conflict].
+type ConflictOwner = WorldFunction
+
+// Conflicts returns the freestanding function names that are used more than once
+// in [World] w, considering functions imported or exported directly by w and
+// functions in each interface w imports or exports.
+// It returns nil if no names conflict.
+func (w *World) Conflicts() []Conflict {
+	var names []string
+	owners := make(map[string][]ConflictOwner)
+	for _, wf := range w.Functions() {
+		f := wf.Function
+		if !f.IsFreestanding() {
+			continue
+		}
+		if _, ok := owners[f.Name]; !ok {
+			names = append(names, f.Name)
+		}
+		owners[f.Name] = append(owners[f.Name], wf)
+	}
+
+	var conflicts []Conflict
+	for _, name := range names {
+		if len(owners[name]) > 1 {
+			conflicts = append(conflicts, Conflict{Name: name, Owners: owners[name]})
+		}
+	}
+	return conflicts
+}
+
+// WorldFunction is a [Function] imported into or exported from a [World],
+// either directly or as part of an [Interface].
+type WorldFunction struct {
+	// Owner is the World for a function imported or exported directly,
+	// otherwise the Interface that contains the function.
+	Owner     TypeOwner
+	Direction Direction
+	Function  *Function
+}
+
+// Functions returns every function imported into or exported from [World] w,
+// including freestanding functions, resource methods, and the functions in each
+// interface w imports or exports, in the order they appear in the imports, then exports.
+// Unlike [World.AllFunctions], which yields only the functions owned by w itself,
+// Functions includes the functions of interfaces.
+func (w *World) Functions() []WorldFunction {
+	var funcs []WorldFunction
+	visit := func(dir Direction) func(string, WorldItem) bool {
+		return func(_ string, item WorldItem) bool {
+			switch v := item.(type) {
+			case *Function:
+				funcs = append(funcs, WorldFunction{Owner: w, Direction: dir, Function: v})
+			case *InterfaceRef:
+				v.Interface.Functions.All()(func(_ string, f *Function) bool {
+					funcs = append(funcs, WorldFunction{Owner: v.Interface, Direction: dir, Function: f})
+					return true
+				})
+			}
+			return true
+		}
+	}
+	w.Imports.All()(visit(Imported))
+	w.Exports.All()(visit(Exported))
+	return funcs
+}
+
 func (w *World) dependsOn(dep Node) bool {
 	if dep == w || dep == w.Package {
 		return true
@@ -146,3 +440,112 @@ type WorldItem interface {
 type _worldItem struct{}
 
 func (_worldItem) isWorldItem() {}
+
+// WorldItemKind identifies the concrete type of a [WorldItem].
+type WorldItemKind int
+
+const (
+	// UnknownWorldItem is the kind of a nil or unrecognized WorldItem.
+	UnknownWorldItem WorldItemKind = iota
+
+	// InterfaceItem is the kind of an [InterfaceRef].
+	InterfaceItem
+
+	// TypeItem is the kind of a [TypeDef].
+	TypeItem
+
+	// FunctionItem is the kind of a [Function].
+	FunctionItem
+)
+
+// String implements [fmt.Stringer], returning "interface", "type", or "function".
+func (k WorldItemKind) String() string {
+	switch k {
+	case InterfaceItem:
+		return "interface"
+	case TypeItem:
+		return "type"
+	case FunctionItem:
+		return "function"
+	}
+	return "<unknown WorldItemKind>"
+}
+
+// WorldItemKindOf returns the [WorldItemKind] of [WorldItem] item.
+func WorldItemKindOf(item WorldItem) WorldItemKind {
+	switch item := item.(type) {
+	case *InterfaceRef:
+		if item != nil {
+			return InterfaceItem
+		}
+	case *TypeDef:
+		if item != nil {
+			return TypeItem
+		}
+	case *Function:
+		if item != nil {
+			return FunctionItem
+		}
+	}
+	return UnknownWorldItem
+}
+
+// AsInterface returns the [Interface] referred to by item if item is an [InterfaceRef].
+func AsInterface(item WorldItem) (*Interface, bool) {
+	ref, ok := item.(*InterfaceRef)
+	if !ok || ref == nil {
+		return nil, false
+	}
+	return ref.Interface, true
+}
+
+// AsTypeDef returns item as a [TypeDef] if it is one.
+func AsTypeDef(item WorldItem) (*TypeDef, bool) {
+	t, ok := item.(*TypeDef)
+	return t, ok && t != nil
+}
+
+// AsFunction returns item as a [Function] if it is one.
+func AsFunction(item WorldItem) (*Function, bool) {
+	f, ok := item.(*Function)
+	return f, ok && f != nil
+}
+
+// WASIVersions returns the versions of the [WASI] packages, those in the "wasi" namespace,
+// imported by [World] w, in ascending order without duplicates. This includes the packages of
+// imported interfaces and the interfaces they use, and of types imported with use statements.
+// Unversioned WASI packages are ignored. A world that targets a single WASI release
+// returns one version; more than one version indicates a world that mixes WASI releases.
+//
+// [WASI]: https://wasi.dev/
+func (w *World) WASIVersions() []semver.Version {
+	var versions []semver.Version
+	add := func(p *Package) {
+		if p == nil || p.Name.Namespace != "wasi" || p.Name.Version == nil {
+			return
+		}
+		v := *p.Name.Version
+		if !slices.ContainsFunc(versions, func(other semver.Version) bool { return other.Equal(v) }) {
+			versions = append(versions, v)
+		}
+	}
+	addInterface := func(i *Interface) {
+		add(i.Package)
+		for _, dep := range i.Dependencies() {
+			add(dep.Package)
+		}
+	}
+	w.Imports.All()(func(_ string, item WorldItem) bool {
+		switch item := item.(type) {
+		case *InterfaceRef:
+			addInterface(item.Interface)
+		case *TypeDef:
+			if i, ok := item.Root().Owner.(*Interface); ok {
+				addInterface(i)
+			}
+		}
+		return true
+	})
+	slices.SortFunc(versions, func(a, b semver.Version) int { return a.Compare(b) })
+	return versions
+}
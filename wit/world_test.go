@@ -0,0 +1,682 @@
+package wit
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWorldConflicts(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:bar;
+
+interface a {
+	resource r {
+		get: func() -> u32;
+	}
+	get: func() -> u32;
+	set: func(v: u32);
+}
+
+interface b {
+	get: func() -> string;
+	reset: func();
+}
+
+world w {
+	import a;
+	import b;
+	import reset: func();
+	export a;
+	export run: func();
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := res.Worlds[0]
+	a := w.Package.Interfaces.Get("a")
+	b := w.Package.Interfaces.Get("b")
+
+	type owner struct {
+		owner TypeOwner
+		dir   Direction
+	}
+	want := map[string][]owner{
+		"get":   {{a, Imported}, {b, Imported}, {a, Exported}},
+		"set":   {{a, Imported}, {a, Exported}},
+		"reset": {{b, Imported}, {w, Imported}},
+	}
+
+	conflicts := w.Conflicts()
+	var names []string
+	for _, c := range conflicts {
+		names = append(names, c.Name)
+		owners := want[c.Name]
+		if len(c.Owners) != len(owners) {
+			t.Errorf("Conflict %q: %d owners, expected %d", c.Name, len(c.Owners), len(owners))
+			continue
+		}
+		for i, o := range c.Owners {
+			if o.Owner != owners[i].owner || o.Direction != owners[i].dir || o.Function.Name != c.Name {
+				t.Errorf("Conflict %q: owner %d: got %s %s, expected %s %s", c.Name, i, o.Direction, o.Owner.WITKind(), owners[i].dir, owners[i].owner.WITKind())
+			}
+		}
+	}
+	if got, want := strings.Join(names, " "), "get set reset"; got != want {
+		t.Errorf("Conflicts(): names %q, expected %q", got, want)
+	}
+}
+
+func TestWorldSortedItems(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:bar;
+
+interface z {}
+interface a {}
+
+world w {
+	import z;
+	import c: func();
+	import a;
+	import b: func();
+	export y: func();
+	export x: func();
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := res.Worlds[0]
+	names := func(items []NamedWorldItem) string {
+		var s []string
+		for _, item := range items {
+			s = append(s, item.Name)
+			if v, _ := w.Imports.GetOK(item.Name); v != item.Item {
+				if v, _ := w.Exports.GetOK(item.Name); v != item.Item {
+					t.Errorf("item %s not found in world", item.Name)
+				}
+			}
+		}
+		return strings.Join(s, " ")
+	}
+	if got, want := names(w.SortedImports()), "foo:bar/z c foo:bar/a b"; got != want {
+		t.Errorf("SortedImports(): %q, expected %q", got, want)
+	}
+	if got, want := names(w.SortedExports()), "y x"; got != want {
+		t.Errorf("SortedExports(): %q, expected %q", got, want)
+	}
+}
+
+func TestWorldItemKind(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:bar;
+
+interface i {}
+
+world w {
+	import i;
+	type t = u32;
+	import f: func();
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := res.Worlds[0]
+	tests := []struct {
+		name string
+		want WorldItemKind
+	}{
+		{"foo:bar/i", InterfaceItem},
+		{"t", TypeItem},
+		{"f", FunctionItem},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := w.Imports.Get(tt.name)
+			if got := WorldItemKindOf(item); got != tt.want {
+				t.Errorf("WorldItemKindOf(%s): %v, expected %v", tt.name, got, tt.want)
+			}
+			i, iok := AsInterface(item)
+			td, tok := AsTypeDef(item)
+			f, fok := AsFunction(item)
+			if iok != (tt.want == InterfaceItem) || (i != nil) != iok {
+				t.Errorf("AsInterface(%s): %v, %t", tt.name, i, iok)
+			}
+			if tok != (tt.want == TypeItem) || (td != nil) != tok {
+				t.Errorf("AsTypeDef(%s): %v, %t", tt.name, td, tok)
+			}
+			if fok != (tt.want == FunctionItem) || (f != nil) != fok {
+				t.Errorf("AsFunction(%s): %v, %t", tt.name, f, fok)
+			}
+		})
+	}
+	if got := WorldItemKindOf(nil); got != UnknownWorldItem {
+		t.Errorf("WorldItemKindOf(nil): %v, expected %v", got, UnknownWorldItem)
+	}
+}
+
+func TestWorldImportedTypes(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:bar;
+
+interface streams {
+	resource input-stream;
+}
+
+world w {
+	use streams.{input-stream};
+	type t = u32;
+	import f: func(s: borrow<input-stream>) -> t;
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := res.Worlds[0]
+	streams := w.Package.Interfaces.Get("streams")
+	types := w.ImportedTypes()
+	if len(types) != 2 {
+		t.Fatalf("ImportedTypes(): %d types, expected 2", len(types))
+	}
+	if got, want := *types[0].Name, "input-stream"; got != want {
+		t.Errorf("types[0]: %s, expected %s", got, want)
+	}
+	if types[0].Owner != w {
+		t.Errorf("types[0] owner: %v, expected world %s", types[0].Owner, w.Name)
+	}
+	if root := types[0].Root(); root != streams.TypeDefs.Get("input-stream") {
+		t.Errorf("types[0].Root(): %v, expected input-stream in interface streams", root)
+	}
+	if got, want := *types[1].Name, "t"; got != want {
+		t.Errorf("types[1]: %s, expected %s", got, want)
+	}
+	if root := types[1].Root(); root != types[1] {
+		t.Errorf("types[1].Root(): %v, expected itself", root)
+	}
+}
+
+func TestWorldResources(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:bar;
+
+interface streams {
+	resource input-stream;
+	resource output-stream;
+}
+
+interface files {
+	use streams.{input-stream};
+	resource descriptor {
+		read: func() -> input-stream;
+	}
+}
+
+interface handler {
+	use streams.{output-stream};
+	resource request;
+}
+
+world w {
+	import streams;
+	import files;
+	use streams.{output-stream};
+	export handler;
+	record r {}
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := res.Worlds[0]
+	names := func(types []*TypeDef) []string {
+		var s []string
+		for _, t := range types {
+			s = append(s, t.QualifiedName())
+		}
+		return s
+	}
+	if got, want := names(w.ImportedResources()), []string{
+		"foo:bar/streams#input-stream",
+		"foo:bar/streams#output-stream",
+		"foo:bar/files#descriptor",
+	}; !slices.Equal(got, want) {
+		t.Errorf("ImportedResources(): %v, expected %v", got, want)
+	}
+	if got, want := names(w.ExportedResources()), []string{
+		"foo:bar/handler#request",
+	}; !slices.Equal(got, want) {
+		t.Errorf("ExportedResources(): %v, expected %v", got, want)
+	}
+	var tables []string
+	for _, rt := range w.ResourceTables() {
+		if rt.Owner != rt.Resource.Owner {
+			t.Errorf("ResourceTables(): %s has owner %v, expected %v", rt.Resource.QualifiedName(), rt.Owner, rt.Resource.Owner)
+		}
+		tables = append(tables, rt.Direction.String()+" "+rt.Resource.QualifiedName())
+	}
+	if want := []string{
+		"imported foo:bar/streams#input-stream",
+		"imported foo:bar/streams#output-stream",
+		"imported foo:bar/files#descriptor",
+		"exported foo:bar/handler#request",
+	}; !slices.Equal(tables, want) {
+		t.Errorf("ResourceTables(): %v, expected %v", tables, want)
+	}
+}
+
+func TestWorldFunctions(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:bar;
+
+interface a {
+	resource r {
+		get: func() -> u32;
+	}
+	f: func();
+}
+
+world w {
+	import a;
+	import g: func();
+	export h: func();
+	export a2: interface {
+		i: func();
+	}
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := res.Worlds[0]
+	var got []string
+	for _, wf := range w.Functions() {
+		got = append(got, wf.Direction.String()+" "+wf.Function.Name)
+		if wf.Owner == nil {
+			t.Errorf("function %s has nil owner", wf.Function.Name)
+		}
+	}
+	want := []string{
+		"imported [method]r.get",
+		"imported f",
+		"imported g",
+		"exported h",
+		"exported i",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("Functions(): %v, expected %v", got, want)
+	}
+}
+
+func TestWorldUsesIncludes(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:bar;
+
+interface types {
+	type t = u32;
+	type u = string;
+}
+
+interface i {}
+
+world base {
+	import i;
+	import f: func();
+	export g: func() -> u32;
+}
+
+world w {
+	use types.{t, u as v};
+	include base with { f as h };
+	import f: func();
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := res.Packages[0]
+	base, w := p.Worlds.Get("base"), p.Worlds.Get("w")
+
+	if len(w.Uses) != 1 {
+		t.Fatalf("Uses: %d, expected 1", len(w.Uses))
+	}
+	use := w.Uses[0]
+	if use.Interface != p.Interfaces.Get("types") {
+		t.Errorf("Uses[0].Interface: %s, expected types", interfaceName(use.Interface))
+	}
+	var names []string
+	for _, t := range use.Types {
+		names = append(names, t.TypeName()+"="+t.Kind.(*TypeDef).TypeName())
+	}
+	if want := []string{"t=t", "v=u"}; !slices.Equal(names, want) {
+		t.Errorf("Uses[0].Types: %v, expected %v", names, want)
+	}
+
+	if len(w.Includes) != 1 {
+		t.Fatalf("Includes: %d, expected 1", len(w.Includes))
+	}
+	inc := w.Includes[0]
+	if inc.World != base {
+		t.Errorf("Includes[0].World: %s, expected base", inc.World.Name)
+	}
+	if want := map[string]string{"f": "h"}; !maps.Equal(inc.With, want) {
+		t.Errorf("Includes[0].With: %v, expected %v", inc.With, want)
+	}
+	if base.Uses != nil || base.Includes != nil {
+		t.Errorf("base: Uses %v, Includes %v, expected nil", base.Uses, base.Includes)
+	}
+
+	var imports, exports []string
+	w.Imports.All()(func(name string, _ WorldItem) bool {
+		imports = append(imports, name)
+		return true
+	})
+	w.Exports.All()(func(name string, _ WorldItem) bool {
+		exports = append(exports, name)
+		return true
+	})
+	if want := []string{"foo:bar/types", "t", "v", "f", "foo:bar/i", "h"}; !slices.Equal(imports, want) {
+		t.Errorf("Imports: %v, expected %v", imports, want)
+	}
+	if want := []string{"g"}; !slices.Equal(exports, want) {
+		t.Errorf("Exports: %v, expected %v", exports, want)
+	}
+	if h := w.Imports.Get("h").(*Function); h.Name != "h" || h == base.Imports.Get("f") {
+		t.Errorf("renamed function: %s, expected a copy named h", h.Name)
+	}
+	if g := w.Exports.Get("g").(*Function); g == base.Exports.Get("g") || g.Name != "g" {
+		t.Errorf("Exports[g]: expected a copy of the function from base")
+	}
+}
+
+func TestWorldIncludeOwner(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:bar;
+
+world base {
+	type t = u32;
+	record r { v: t }
+	import f: func(r: r) -> list<t>;
+	import i: interface {
+		record x { v: u32 }
+		g: func() -> x;
+	}
+}
+
+world w {
+	include base;
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := res.Packages[0]
+	base, w := p.Worlds.Get("base"), p.Worlds.Get("w")
+
+	tt := w.Imports.Get("t").(*TypeDef)
+	if tt == base.Imports.Get("t") || tt.Owner != w {
+		t.Errorf("Imports[t].Owner: %v, expected w", tt.Owner)
+	}
+	r := w.Imports.Get("r").(*TypeDef)
+	if r.Owner != w || r.Kind.(*Record).Fields[0].Type != tt {
+		t.Errorf("Imports[r]: expected a copy owned by w referring to t in w")
+	}
+	f := w.Imports.Get("f").(*Function)
+	if f.Params[0].Type != r {
+		t.Errorf("Imports[f].Params[0].Type: expected r in w")
+	}
+	if l := f.Results[0].Type.(*TypeDef).Kind.(*List); l.Type != tt {
+		t.Errorf("Imports[f].Results[0].Type: expected list<t> of t in w")
+	}
+
+	i := w.Imports.Get("i").(*InterfaceRef).Interface
+	if i == base.Imports.Get("i").(*InterfaceRef).Interface {
+		t.Fatalf("Imports[i]: expected a copy of the inline interface")
+	}
+	x := i.TypeDefs.Get("x")
+	if x.Owner != i {
+		t.Errorf("Imports[i] x.Owner: %v, expected the copied interface", x.Owner)
+	}
+	if g := i.Functions.Get("g"); g.Results[0].Type != x {
+		t.Errorf("Imports[i] g result: expected x in the copied interface")
+	}
+	if !slices.Contains(res.Interfaces, i) || !slices.Contains(res.TypeDefs, tt) || !slices.Contains(res.TypeDefs, x) {
+		t.Errorf("Resolve: expected copied interface and types to be registered")
+	}
+}
+
+func TestWorldIncludeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		wit  string
+	}{
+		{"unknown world", `package foo:bar; world w { include x; }`},
+		{"unknown name", `package foo:bar; world a {} world w { include a with { f as g }; }`},
+		{"cycle", `package foo:bar; world a { include b; } world b { include a; }`},
+		{"duplicate", `package foo:bar; world a { import f: func(); } world w { import f: func(); include a; }`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseWIT(strings.NewReader(tt.wit))
+			if err == nil {
+				t.Errorf("ParseWIT: expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestWorldPrimaryExport(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:bar;
+
+interface a {}
+interface b {}
+
+world none {
+	import a;
+	export f: func();
+}
+
+world one {
+	import b;
+	export a;
+	export f: func();
+}
+
+world inline {
+	export i: interface {}
+}
+
+world two {
+	export a;
+	export b;
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := res.Packages[0]
+	tests := []struct {
+		world string
+		want  *Interface
+	}{
+		{"none", nil},
+		{"one", p.Interfaces.Get("a")},
+		{"inline", p.Worlds.Get("inline").Exports.Get("i").(*InterfaceRef).Interface},
+		{"two", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.world, func(t *testing.T) {
+			got, ok := p.Worlds.Get(tt.world).PrimaryExport()
+			if got != tt.want || ok != (tt.want != nil) {
+				t.Errorf("PrimaryExport(): %v, %t, expected %v", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorldHash(t *testing.T) {
+	const base = `package foo:bar;
+
+interface types {
+	record point { x: u32, y: u32 }
+	resource r {
+		get: func() -> point;
+	}
+}
+
+interface api {
+	use types.{point, r};
+	/// Moves a point.
+	move: func(p: point) -> point;
+	take: func(r: own<r>);
+}
+
+world w {
+	import api;
+	export run: func();
+}
+`
+	tests := []struct {
+		name string
+		wit  string
+		same bool
+	}{
+		{"identical", base, true},
+		{"docs", strings.Replace(base, "/// Moves a point.", "/// Moves a point somewhere else.", 1), true},
+		{"function order", strings.Replace(base, `	/// Moves a point.
+	move: func(p: point) -> point;
+	take: func(r: own<r>);`, `	take: func(r: own<r>);
+	move: func(p: point) -> point;`, 1), true},
+		{"import order", strings.Replace(base, `	import api;
+	export run: func();`, `	export run: func();
+	import api;`, 1), true},
+		{"field type", strings.Replace(base, "y: u32", "y: u64", 1), false},
+		{"field order", strings.Replace(base, "x: u32, y: u32", "y: u32, x: u32", 1), false},
+		{"method result", strings.Replace(base, "get: func() -> point;", "get: func() -> u32;", 1), false},
+		{"param name", strings.Replace(base, "move: func(p: point)", "move: func(q: point)", 1), false},
+		{"new export", strings.Replace(base, "export run: func();", "export run: func();\n\texport stop: func();", 1), false},
+		{"gate", strings.Replace(base, "\tmove:", "\t@unstable(feature = fancy)\n\tmove:", 1), false},
+	}
+	hash := func(t *testing.T, s string) [32]byte {
+		res, err := ParseWIT(strings.NewReader(s))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.Worlds[0].Hash()
+	}
+	want := hash(t, base)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := hash(t, tt.wit)
+			if (got == want) != tt.same {
+				t.Errorf("Hash() equal: %t, expected %t", got == want, tt.same)
+			}
+		})
+	}
+}
+
+func TestWorldWASIVersions(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:bar@1.0.0;
+
+interface local {}
+
+world mixed {
+	import wasi:clocks/monotonic-clock@0.2.1;
+	import wasi:random/random;
+	import local;
+}
+
+world uses {
+	use wasi:io/streams@0.2.0.{input-stream};
+	import read: func(s: borrow<input-stream>);
+}
+
+world none {
+	import local;
+	export wasi:clocks/monotonic-clock@0.2.1;
+}
+
+package wasi:io@0.2.0 {
+	interface streams {
+		resource input-stream;
+	}
+}
+
+package wasi:clocks@0.2.1 {
+	interface monotonic-clock {
+		use wasi:io/streams@0.2.0.{input-stream};
+	}
+}
+
+package wasi:random {
+	interface random {}
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		world string
+		want  []string
+	}{
+		{"mixed", []string{"0.2.0", "0.2.1"}},
+		{"uses", []string{"0.2.0"}},
+		{"none", []string{"0.2.0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.world, func(t *testing.T) {
+			i := slices.IndexFunc(res.Worlds, func(w *World) bool { return w.Name == tt.world })
+			if i < 0 {
+				t.Fatalf("world %s not found", tt.world)
+			}
+			var got []string
+			for _, v := range res.Worlds[i].WASIVersions() {
+				got = append(got, v.String())
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("WASIVersions(): %v, expected %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorldImportedExportedInterfaces(t *testing.T) {
+	res, err := ParseWIT(strings.NewReader(`package foo:bar@0.1.0;
+
+interface a {}
+
+interface b {}
+
+world w {
+	import a;
+	import f: func();
+	import inline: interface {
+		g: func();
+	}
+	@since(version = 0.1.0)
+	export b;
+	export run: func();
+}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := res.Packages[0].Worlds.Get("w")
+	names := func(faces []*NamedInterface) []string {
+		var s []string
+		for _, f := range faces {
+			s = append(s, f.Name)
+		}
+		return s
+	}
+
+	imports := w.ImportedInterfaces()
+	if got, want := names(imports), []string{"foo:bar/a@0.1.0", "inline"}; !slices.Equal(got, want) {
+		t.Errorf("ImportedInterfaces(): %v, expected %v", got, want)
+	}
+	if len(imports) == 2 && (imports[0].Interface != res.Packages[0].Interfaces.Get("a") || imports[1].Interface.Name != nil) {
+		t.Errorf("ImportedInterfaces(): wrong interfaces")
+	}
+
+	exports := w.ExportedInterfaces()
+	if got, want := names(exports), []string{"foo:bar/b@0.1.0"}; !slices.Equal(got, want) {
+		t.Errorf("ExportedInterfaces(): %v, expected %v", got, want)
+	}
+	if len(exports) == 1 && exports[0].Stability == nil {
+		t.Errorf("ExportedInterfaces(): expected Stability of export")
+	}
+}